@@ -0,0 +1,316 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+	"github.com/zircuit-labs/consensus-proxy/cmd/loadbalancer"
+)
+
+// StatusResponse is the state of the proxy returned by `/admin/status`
+type StatusResponse struct {
+	Primary       string         `json:"primary"`        // empty if no node is primary
+	ManualPrimary string         `json:"manual_primary"` // node promoted by an operator, empty without an override
+	Strategy      string         `json:"strategy"`       // routing strategy used outside of routes
+	TotalNodes    int            `json:"total_nodes"`
+	HealthyNodes  int            `json:"healthy_nodes"`
+	Nodes         []NodeResponse `json:"nodes"`
+}
+
+// NodeResponse is the state of a single beacon node
+type NodeResponse struct {
+	Name                 string          `json:"name"`
+	Endpoint             string          `json:"endpoint"` // scheme and host only, as node URLs may embed credentials
+	Type                 string          `json:"type"`
+	Priority             int             `json:"priority"`
+	OriginalPriority     int             `json:"original_priority"`
+	Primary              bool            `json:"primary"`
+	Healthy              bool            `json:"healthy"`
+	ConsecutiveErrors    int64           `json:"consecutive_errors"`
+	ConsecutiveSuccesses int64           `json:"consecutive_successes"`
+	TotalFailures        int64           `json:"total_failures"`
+	Requests             int64           `json:"requests"`
+	Outstanding          int64           `json:"outstanding"`
+	Streams              int64           `json:"streams"` // open websockets and event streams
+	CircuitState         string          `json:"circuit_state"`
+	Forked               bool            `json:"forked"`
+	Cordoned             bool            `json:"cordoned"`
+	Draining             bool            `json:"draining"`
+	Drained              bool            `json:"drained"`   // draining with no requests or streams left
+	HeadSlot             *uint64         `json:"head_slot"` // null until the head has been fetched
+	LastCheck            time.Time       `json:"last_check"`
+	LastHealthError      *HealthError    `json:"last_health_error"` // null if the latest check passed
+	Probes               []ProbeResponse `json:"probes"`
+}
+
+// HealthError describes why a health check failed
+type HealthError struct {
+	Probe  string `json:"probe"`
+	Reason string `json:"reason"`
+	Error  string `json:"error"`
+}
+
+// ProbeResponse is the result of one probe in a node's latest health check
+type ProbeResponse struct {
+	Probe   string       `json:"probe"`
+	Healthy bool         `json:"healthy"`
+	Error   *HealthError `json:"error"`
+}
+
+// NewHandler creates the admin API handler. It is meant to be served on its own listener,
+// away from client traffic. The endpoints that change state require token as a bearer token,
+// and are refused altogether without one.
+//
+//	GET    /admin/status                  primary, strategy and every node
+//	GET    /admin/nodes                   every node
+//	GET    /admin/nodes/{name}            a single node
+//	POST   /admin/nodes/{name}/cordon     stop sending new requests to a node
+//	POST   /admin/nodes/{name}/drain      cordon a node and wait up to ?wait= for it to drain
+//	POST   /admin/nodes/{name}/uncordon   put a cordoned or draining node back into rotation
+//	POST   /admin/nodes/{name}/promote    make a node primary until the override is cleared
+//	DELETE /admin/primary                 clear the primary override
+func NewHandler(lb *loadbalancer.LoadBalancer, token config.Secret) http.Handler {
+	mux := http.NewServeMux()
+	mutating := func(pattern string, handler http.HandlerFunc) {
+		mux.HandleFunc(pattern, requireToken(token, handler))
+	}
+
+	mux.HandleFunc("GET /admin/status", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, statusResponse(lb))
+	})
+
+	mux.HandleFunc("GET /admin/nodes", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, nodeResponses(lb))
+	})
+
+	mux.HandleFunc("GET /admin/nodes/{name}", func(w http.ResponseWriter, r *http.Request) {
+		writeNode(w, lb, r.PathValue("name"), http.StatusOK)
+	})
+
+	mutating("POST /admin/nodes/{name}/cordon", nodeAction(lb, lb.Cordon))
+	mutating("POST /admin/nodes/{name}/uncordon", nodeAction(lb, lb.Uncordon))
+	mutating("POST /admin/nodes/{name}/promote", nodeAction(lb, lb.ForcePrimary))
+
+	mutating("POST /admin/nodes/{name}/drain", func(w http.ResponseWriter, r *http.Request) {
+		var wait time.Duration
+		if raw := r.URL.Query().Get("wait"); raw != "" {
+			var err error
+			if wait, err = time.ParseDuration(raw); err != nil || wait < 0 {
+				writeError(w, http.StatusBadRequest, "invalid wait duration: "+raw)
+				return
+			}
+		}
+
+		name := r.PathValue("name")
+		if err := lb.Drain(name); err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// Answer 200 once drained, or 202 if the node is still draining when the wait is over
+		status := http.StatusAccepted
+		if waitDrained(r, lb.GetNode(name), wait) {
+			status = http.StatusOK
+		}
+		writeNode(w, lb, name, status)
+	})
+
+	mutating("DELETE /admin/primary", func(w http.ResponseWriter, r *http.Request) {
+		lb.ClearPrimaryOverride()
+		writeJSON(w, http.StatusOK, statusResponse(lb))
+	})
+
+	return mux
+}
+
+// requireToken only passes requests that present token as a bearer token on to handler. The
+// token is compared in constant time, and without a configured token every request is refused.
+func requireToken(token config.Secret, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token == "" {
+			writeError(w, http.StatusForbidden, "admin token is not configured")
+			return
+		}
+		presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(presented), []byte(token.Value())) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "a valid admin token is required")
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// nodeAction handles an operator action on the node named in the path, answering with the
+// node's state afterwards. Actions on unknown nodes fail with 404, other failures with 409.
+func nodeAction(lb *loadbalancer.LoadBalancer, action func(name string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		if lb.GetNode(name) == nil {
+			writeError(w, http.StatusNotFound, "unknown node: "+name)
+			return
+		}
+		if err := action(name); err != nil {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		writeNode(w, lb, name, http.StatusOK)
+	}
+}
+
+// drainPollInterval is how often a drain request checks whether the node has drained
+const drainPollInterval = 100 * time.Millisecond
+
+// waitDrained waits up to wait for a draining node to finish its requests and streams,
+// returning whether it has drained
+func waitDrained(r *http.Request, node *beaconnode.BeaconNode, wait time.Duration) bool {
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for !node.IsDrained() {
+		select {
+		case <-r.Context().Done():
+			return false
+		case <-deadline.C:
+			return node.IsDrained()
+		case <-ticker.C:
+		}
+	}
+	return true
+}
+
+// statusResponse describes the proxy and every node
+func statusResponse(lb *loadbalancer.LoadBalancer) StatusResponse {
+	nodes := nodeResponses(lb)
+	healthy := 0
+	for _, node := range nodes {
+		if node.Healthy {
+			healthy++
+		}
+	}
+
+	status := StatusResponse{
+		Strategy:     lb.StrategyName(),
+		TotalNodes:   len(nodes),
+		HealthyNodes: healthy,
+		Nodes:        nodes,
+	}
+	if primary := lb.GetPrimary(); primary != nil {
+		status.Primary = primary.Name
+	}
+	if manual := lb.ManualPrimary(); manual != nil {
+		status.ManualPrimary = manual.Name
+	}
+	return status
+}
+
+// writeNode writes the state of the named node with the given status, or 404 if there is none
+func writeNode(w http.ResponseWriter, lb *loadbalancer.LoadBalancer, name string, status int) {
+	for _, node := range nodeResponses(lb) {
+		if node.Name == name {
+			writeJSON(w, status, node)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "unknown node: "+name)
+}
+
+// nodeResponses describes every node in configuration order
+func nodeResponses(lb *loadbalancer.LoadBalancer) []NodeResponse {
+	healthy := make(map[*beaconnode.BeaconNode]bool)
+	for _, node := range lb.GetHealthyNodes() {
+		healthy[node] = true
+	}
+
+	nodes := make([]NodeResponse, 0, len(lb.GetNodes()))
+	for _, node := range lb.GetNodes() {
+		nodes = append(nodes, nodeResponse(node, healthy[node]))
+	}
+	return nodes
+}
+
+// nodeResponse describes a single node
+func nodeResponse(node *beaconnode.BeaconNode, healthy bool) NodeResponse {
+	consecutiveErrors, totalFailures, requests := node.GetStats()
+	lastCheck, lastErr := node.LastHealthCheck()
+
+	resp := NodeResponse{
+		Name:                 node.Name,
+		Endpoint:             endpoint(node.URL),
+		Type:                 node.Type,
+		Priority:             node.GetPriority(),
+		OriginalPriority:     node.OriginalPriority,
+		Primary:              node.IsPrimary(),
+		Healthy:              healthy,
+		ConsecutiveErrors:    consecutiveErrors,
+		ConsecutiveSuccesses: node.GetConsecutiveSuccesses(),
+		TotalFailures:        totalFailures,
+		Requests:             requests,
+		Outstanding:          node.GetOutstanding(),
+		Streams:              node.GetStreams(),
+		CircuitState:         node.Breaker.State().String(),
+		Forked:               node.IsForked(),
+		Cordoned:             node.IsCordoned(),
+		Draining:             node.IsDraining(),
+		Drained:              node.IsDrained(),
+		LastCheck:            lastCheck,
+		LastHealthError:      healthError(lastErr),
+		Probes:               make([]ProbeResponse, 0),
+	}
+	if slot, _, ok := node.GetHead(); ok {
+		resp.HeadSlot = &slot
+	}
+	for _, result := range node.ProbeResults() {
+		resp.Probes = append(resp.Probes, ProbeResponse{
+			Probe:   result.Probe,
+			Healthy: result.Healthy,
+			Error:   healthError(result.Error),
+		})
+	}
+	return resp
+}
+
+// healthError converts a health check error for the response, keeping nil as null
+func healthError(err *beaconnode.HealthCheckError) *HealthError {
+	if err == nil {
+		return nil
+	}
+	return &HealthError{
+		Probe:  err.Probe,
+		Reason: err.Reason,
+		Error:  err.Error(),
+	}
+}
+
+// endpoint returns the scheme and host of a node URL, leaving out any credentials in the
+// user info, path or query
+func endpoint(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
+
+// writeJSON writes v as the JSON response body with the given status
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a JSON error response in the beacon API error format
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{
+		"code":    status,
+		"message": message,
+	})
+}
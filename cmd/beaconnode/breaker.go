@@ -0,0 +1,229 @@
+package beaconnode
+
+import (
+	"sync"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+// CircuitState is the state of a node's circuit breaker
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // Node receives traffic normally
+	CircuitHalfOpen                     // Node receives one probe request at a time
+	CircuitOpen                         // Node is out of rotation
+)
+
+// String returns the state name as used in logs and metrics
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitHalfOpen:
+		return "half_open"
+	case CircuitOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// callOutcome is the result of a single request in the breaker's window
+type callOutcome struct {
+	failed bool
+	slow   bool
+}
+
+// CircuitBreaker tracks the error and slow-call rates of recent requests to a node.
+// The circuit opens when either rate crosses its threshold, keeping the node out of
+// rotation for open_duration. It then turns half-open and lets probe requests through
+// one at a time: enough consecutive successes close it, any failure opens it again.
+type CircuitBreaker struct {
+	mu             sync.Mutex
+	cfg            config.BreakerConfig
+	state          CircuitState
+	outcomes       []callOutcome
+	next           int
+	openedAt       time.Time
+	probeSuccesses int
+	probesInFlight int
+	onStateChange  func(from, to CircuitState)
+	now            func() time.Time
+}
+
+// NewCircuitBreaker creates a closed circuit breaker
+func NewCircuitBreaker(cfg config.BreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{
+		cfg: cfg,
+		now: time.Now,
+	}
+}
+
+// OnStateChange registers a function that is called after every state transition
+func (cb *CircuitBreaker) OnStateChange(fn func(from, to CircuitState)) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.onStateChange = fn
+}
+
+// State returns the current state of the circuit
+func (cb *CircuitBreaker) State() CircuitState {
+	if cb == nil {
+		return CircuitClosed
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// Available reports whether the node could take a request now: the circuit is closed, half-open
+// with no probe in flight, or open for at least open_duration. It does not change the state or
+// take the probe slot, so it is safe for filtering nodes that may never be tried.
+func (cb *CircuitBreaker) Available() bool {
+	if cb == nil || !cb.cfg.Enabled {
+		return true
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	switch cb.state {
+	case CircuitHalfOpen:
+		return cb.probesInFlight == 0
+	case CircuitOpen:
+		return cb.now().Sub(cb.openedAt) >= cb.cfg.OpenDuration
+	default:
+		return true
+	}
+}
+
+// TryAcquire admits a request to the node, reporting false if the circuit does not allow one.
+// An open circuit turns half-open once open_duration has passed, and a half-open circuit admits
+// a single probe until its outcome is recorded. Admitted requests must end with Record or Abandon.
+func (cb *CircuitBreaker) TryAcquire() bool {
+	if cb == nil || !cb.cfg.Enabled {
+		return true
+	}
+
+	cb.mu.Lock()
+	notify := func() {}
+	if cb.state == CircuitOpen && cb.now().Sub(cb.openedAt) >= cb.cfg.OpenDuration {
+		notify = cb.transition(CircuitHalfOpen)
+	}
+	admitted := false
+	switch cb.state {
+	case CircuitClosed:
+		admitted = true
+	case CircuitHalfOpen:
+		if cb.probesInFlight == 0 {
+			cb.probesInFlight++
+			admitted = true
+		}
+	}
+	cb.mu.Unlock()
+
+	notify()
+	return admitted
+}
+
+// Abandon reports that a request ended without saying anything about the node, such as
+// when the client went away, so that a half-open circuit can send another probe
+func (cb *CircuitBreaker) Abandon() {
+	if cb == nil || !cb.cfg.Enabled {
+		return
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.state == CircuitHalfOpen && cb.probesInFlight > 0 {
+		cb.probesInFlight--
+	}
+}
+
+// Record reports the outcome of a request to the node. A zero duration is never slow.
+func (cb *CircuitBreaker) Record(duration time.Duration, failed bool) {
+	if cb == nil || !cb.cfg.Enabled {
+		return
+	}
+
+	slow := cb.cfg.SlowCallDuration > 0 && duration >= cb.cfg.SlowCallDuration
+
+	cb.mu.Lock()
+	notify := func() {}
+	switch cb.state {
+	case CircuitClosed:
+		notify = cb.recordClosed(callOutcome{failed: failed, slow: slow})
+	case CircuitHalfOpen:
+		if cb.probesInFlight > 0 {
+			cb.probesInFlight--
+		}
+		if failed || slow {
+			notify = cb.transition(CircuitOpen)
+			break
+		}
+		cb.probeSuccesses++
+		if cb.probeSuccesses >= cb.cfg.HalfOpenProbes {
+			notify = cb.transition(CircuitClosed)
+		}
+	case CircuitOpen:
+		// Requests that started before the circuit opened say nothing new
+	}
+	cb.mu.Unlock()
+
+	notify()
+}
+
+// recordClosed adds an outcome to the window and opens the circuit if a rate crosses its threshold.
+// Must be called with mu held.
+func (cb *CircuitBreaker) recordClosed(outcome callOutcome) func() {
+	if len(cb.outcomes) < cb.cfg.WindowSize {
+		cb.outcomes = append(cb.outcomes, outcome)
+	} else {
+		cb.outcomes[cb.next] = outcome
+		cb.next = (cb.next + 1) % cb.cfg.WindowSize
+	}
+
+	if len(cb.outcomes) < cb.cfg.MinRequests {
+		return func() {}
+	}
+
+	var failures, slow int
+	for _, o := range cb.outcomes {
+		if o.failed {
+			failures++
+		}
+		if o.slow {
+			slow++
+		}
+	}
+	total := float64(len(cb.outcomes))
+	if float64(failures)/total >= cb.cfg.ErrorRateThreshold || float64(slow)/total >= cb.cfg.SlowRateThreshold {
+		return cb.transition(CircuitOpen)
+	}
+	return func() {}
+}
+
+// transition moves the circuit to a new state and returns a function that notifies the
+// state change listener. Must be called with mu held; the returned function must not be.
+func (cb *CircuitBreaker) transition(to CircuitState) func() {
+	from := cb.state
+	cb.state = to
+	cb.probeSuccesses = 0
+	cb.probesInFlight = 0
+
+	switch to {
+	case CircuitOpen:
+		cb.openedAt = cb.now()
+	case CircuitClosed:
+		cb.outcomes = cb.outcomes[:0]
+		cb.next = 0
+	}
+
+	listener := cb.onStateChange
+	return func() {
+		if listener != nil && from != to {
+			listener(from, to)
+		}
+	}
+}
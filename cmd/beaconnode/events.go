@@ -0,0 +1,36 @@
+package beaconnode
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SubscribeEvents opens a Server-Sent Events subscription to the node's `/eth/v1/events` endpoint.
+// The caller owns the returned response body and must close it; cancelling ctx ends the subscription.
+func (bn *BeaconNode) SubscribeEvents(ctx context.Context, topics []string) (*http.Response, error) {
+	query := url.Values{"topics": {strings.Join(topics, ",")}}
+	endpoint := bn.URL + "/eth/v1/events?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	bn.SetUpstreamHeaders(req.Header)
+
+	resp, err := bn.Proxy.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("non-200 status code: %d", resp.StatusCode)
+	}
+
+	return resp, nil
+}
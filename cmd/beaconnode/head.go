@@ -0,0 +1,159 @@
+package beaconnode
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HeadResponse represents the response from /eth/v1/beacon/headers/head endpoint
+type HeadResponse struct {
+	Data struct {
+		Root   string `json:"root"`
+		Header struct {
+			Message struct {
+				Slot string `json:"slot"`
+			} `json:"message"`
+		} `json:"header"`
+	} `json:"data"`
+}
+
+// FetchHead retrieves the node's current head slot and block root from `/eth/v1/beacon/headers/head`
+func (bn *BeaconNode) FetchHead(timeout time.Duration) (uint64, string, error) {
+	client := &http.Client{
+		Timeout: timeout,
+	}
+
+	resp, err := bn.get(client, "/eth/v1/beacon/headers/head")
+	if err != nil {
+		return 0, "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, "", fmt.Errorf("non-200 status code: %d", resp.StatusCode)
+	}
+
+	var headResp HeadResponse
+	if err := json.NewDecoder(resp.Body).Decode(&headResp); err != nil {
+		return 0, "", fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	slot, err := strconv.ParseUint(headResp.Data.Header.Message.Slot, 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid head slot %q: %w", headResp.Data.Header.Message.Slot, err)
+	}
+
+	return slot, headResp.Data.Root, nil
+}
+
+// SetHead records the node's latest known head
+func (bn *BeaconNode) SetHead(slot uint64, root string) {
+	bn.mu.Lock()
+	defer bn.mu.Unlock()
+	bn.headSlot = slot
+	bn.headRoot = root
+	bn.headKnown = true
+}
+
+// GetHead returns the node's latest known head slot and block root.
+// ok is false until the head has been fetched at least once.
+func (bn *BeaconNode) GetHead() (slot uint64, root string, ok bool) {
+	bn.mu.RLock()
+	defer bn.mu.RUnlock()
+	return bn.headSlot, bn.headRoot, bn.headKnown
+}
+
+// BlockRootResponse represents the response from /eth/v1/beacon/blocks/{block_id}/root endpoint
+type BlockRootResponse struct {
+	Data struct {
+		Root string `json:"root"`
+	} `json:"data"`
+}
+
+// FetchBlockRoot retrieves the root of the block at slot on the node's canonical chain from
+// `/eth/v1/beacon/blocks/{slot}/root`. An empty root without error means the slot is empty.
+func (bn *BeaconNode) FetchBlockRoot(slot uint64, timeout time.Duration) (string, error) {
+	client := &http.Client{
+		Timeout: timeout,
+	}
+
+	resp, err := bn.get(client, fmt.Sprintf("/eth/v1/beacon/blocks/%d/root", slot))
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("non-200 status code: %d", resp.StatusCode)
+	}
+
+	var rootResp BlockRootResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rootResp); err != nil {
+		return "", fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return rootResp.Data.Root, nil
+}
+
+// FetchFinalizedCheckpoint retrieves the node's finalized checkpoint epoch and root from
+// `/eth/v1/beacon/states/head/finality_checkpoints`
+func (bn *BeaconNode) FetchFinalizedCheckpoint(timeout time.Duration) (uint64, string, error) {
+	client := &http.Client{
+		Timeout: timeout,
+	}
+
+	resp, err := bn.get(client, "/eth/v1/beacon/states/head/finality_checkpoints")
+	if err != nil {
+		return 0, "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, "", fmt.Errorf("non-200 status code: %d", resp.StatusCode)
+	}
+
+	var finalityResp FinalityCheckpointsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&finalityResp); err != nil {
+		return 0, "", fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	epoch, err := strconv.ParseUint(finalityResp.Data.Finalized.Epoch, 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid finalized epoch %q: %w", finalityResp.Data.Finalized.Epoch, err)
+	}
+	return epoch, finalityResp.Data.Finalized.Root, nil
+}
+
+// SetForked marks whether the node is on a minority fork and reports whether that changed
+func (bn *BeaconNode) SetForked(forked bool) bool {
+	bn.mu.Lock()
+	defer bn.mu.Unlock()
+	changed := bn.forked != forked
+	bn.forked = forked
+	return changed
+}
+
+// RecordForkVerdict records whether the latest fork comparison found the node disagreeing with
+// the majority, and returns how many consecutive comparisons it has disagreed in
+func (bn *BeaconNode) RecordForkVerdict(disagreed bool) int {
+	bn.mu.Lock()
+	defer bn.mu.Unlock()
+	if disagreed {
+		bn.forkStrikes++
+	} else {
+		bn.forkStrikes = 0
+	}
+	return bn.forkStrikes
+}
+
+// IsForked returns whether the node was last seen on a minority fork
+func (bn *BeaconNode) IsForked() bool {
+	bn.mu.RLock()
+	defer bn.mu.RUnlock()
+	return bn.forked
+}
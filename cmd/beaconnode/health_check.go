@@ -1,10 +1,11 @@
 package beaconnode
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/zircuit-labs/consensus-proxy/cmd/config"
 	"github.com/zircuit-labs/consensus-proxy/cmd/logger"
@@ -15,60 +16,30 @@ type SyncingResponse struct {
 	Data struct {
 		IsSyncing    bool   `json:"is_syncing"`
 		SyncDistance string `json:"sync_distance"`
+		IsOptimistic bool   `json:"is_optimistic"`
+		ELOffline    bool   `json:"el_offline"`
 	} `json:"data"`
 }
 
-// CheckSyncStatus performs a lightweight health check on the beacon node using `/eth/v1/node/syncing` endpoint.
-// Returns true if the node is not syncing (is_syncing=false) and sync_distance=0.
+// CheckSyncStatus performs a lightweight health check on the beacon node by running its probes.
+// Returns true if every probe passes.
 // This is a simpler version without extensive logging, suitable for startup checks.
 func (bn *BeaconNode) CheckSyncStatus(timeout config.HealthCheckConfig) (bool, error) {
-	endpoint := bn.URL + "/eth/v1/node/syncing"
-
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: timeout.Timeout,
-	}
-
-	// Make GET request to syncing endpoint
-	resp, err := client.Get(endpoint)
-	if err != nil {
-		return false, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check HTTP status code
-	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("non-200 status code: %d", resp.StatusCode)
+	if healthErr := firstFailure(bn.runProbes(timeout)); healthErr != nil {
+		return false, healthErr
 	}
-
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return false, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	// Parse JSON response
-	var syncResp SyncingResponse
-	if err := json.Unmarshal(body, &syncResp); err != nil {
-		return false, fmt.Errorf("failed to parse JSON: %w", err)
-	}
-
-	// Check if node is synced: is_syncing must be false and sync_distance must be "0"
-	isHealthy := !syncResp.Data.IsSyncing && syncResp.Data.SyncDistance == "0"
-
-	if !isHealthy {
-		return false, fmt.Errorf("not synced (is_syncing=%t, sync_distance=%s)", syncResp.Data.IsSyncing, syncResp.Data.SyncDistance)
-	}
-
 	return true, nil
 }
 
 // HealthCheckError represents detailed information about a health check failure
 type HealthCheckError struct {
+	Probe        string // type of the probe that failed
 	Reason       string
 	StatusCode   int
 	IsSyncing    bool
 	SyncDistance string
+	IsOptimistic bool
+	ELOffline    bool
 	Err          error
 }
 
@@ -79,101 +50,163 @@ func (e *HealthCheckError) Error() string {
 	return e.Reason
 }
 
-// HealthCheck performs a health check on the beacon node using `/eth/v1/node/syncing` endpoint.
-// Returns true if the node is not syncing (is_syncing=false) and sync_distance=0.
-// Returns an error with detailed information if the check fails.
+// HealthCheck performs a health check on the beacon node by running its probes.
+// Returns true if every probe passes.
+// Returns an error with detailed information about the first failing probe otherwise.
+// The result of every probe is kept and available from ProbeResults.
 func (bn *BeaconNode) HealthCheck(cfg config.HealthCheckConfig) (bool, *HealthCheckError) {
-	endpoint := bn.URL + "/eth/v1/node/syncing"
+	results := bn.runProbes(cfg)
+	healthErr := firstFailure(results)
+	if healthErr == nil {
+		logger.Debug("node health check passed",
+			"node_name", bn.Name,
+			"priority", bn.Priority,
+		)
+		return true, nil
+	}
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: cfg.Timeout,
+	for _, result := range results {
+		if !result.Healthy {
+			bn.logProbeFailure(result.Error)
+		}
 	}
+	return false, healthErr
+}
 
-	// Make GET request to syncing endpoint
-	resp, err := client.Get(endpoint)
-	if err != nil {
+// logProbeFailure logs why a probe failed
+func (bn *BeaconNode) logProbeFailure(healthErr *HealthCheckError) {
+	switch healthErr.Reason {
+	case "request_failed", "read_body_failed":
 		logger.Warn("health check request failed",
 			"node_name", bn.Name,
-			"error", err,
+			"probe", healthErr.Probe,
+			"reason", healthErr.Reason,
+			"error", healthErr.Err,
 		)
-		return false, &HealthCheckError{
-			Reason: "request_failed",
-			Err:    err,
-		}
-	}
-	defer resp.Body.Close()
-
-	// Check HTTP status code
-	if resp.StatusCode != http.StatusOK {
+	case "non_200_status", "node_health_unavailable":
 		logger.Warn("health check returned non-200 status",
 			"node_name", bn.Name,
-			"status_code", resp.StatusCode,
+			"probe", healthErr.Probe,
+			"reason", healthErr.Reason,
+			"status_code", healthErr.StatusCode,
 		)
-		return false, &HealthCheckError{
-			Reason:     "non_200_status",
-			StatusCode: resp.StatusCode,
-		}
-	}
-
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		logger.Warn("health check failed to read response body",
+	case "json_parse_failed", "invalid_sync_distance":
+		logger.Warn("health check failed to parse JSON response",
 			"node_name", bn.Name,
-			"error", err,
+			"probe", healthErr.Probe,
+			"reason", healthErr.Reason,
+			"error", healthErr.Err,
 		)
-		return false, &HealthCheckError{
-			Reason: "read_body_failed",
-			Err:    err,
-		}
-	}
-
-	// Parse JSON response
-	var syncResp SyncingResponse
-	if err := json.Unmarshal(body, &syncResp); err != nil {
-		logger.Warn("health check failed to parse JSON response",
+	case "peer_count_low", "head_stale", "finality_lag_exceeded":
+		logger.Warn("health check probe failed",
 			"node_name", bn.Name,
-			"error", err,
-			"body", string(body),
+			"probe", healthErr.Probe,
+			"reason", healthErr.Reason,
+			"error", healthErr.Err,
 		)
-		return false, &HealthCheckError{
-			Reason: "json_parse_failed",
-			Err:    err,
-		}
-	}
-
-	// Check if node is synced: is_syncing must be false and sync_distance must be "0"
-	isHealthy := !syncResp.Data.IsSyncing && syncResp.Data.SyncDistance == "0"
-
-	if !isHealthy {
+	default:
 		logger.Info("node not fully synced",
 			"node_name", bn.Name,
-			"is_syncing", syncResp.Data.IsSyncing,
-			"sync_distance", syncResp.Data.SyncDistance,
+			"probe", healthErr.Probe,
+			"reason", healthErr.Reason,
+			"is_syncing", healthErr.IsSyncing,
+			"sync_distance", healthErr.SyncDistance,
+			"is_optimistic", healthErr.IsOptimistic,
+			"el_offline", healthErr.ELOffline,
 		)
+	}
+}
 
-		// Determine the specific reason
-		var reason string
-		if syncResp.Data.IsSyncing {
-			reason = "is_syncing"
-		} else if syncResp.Data.SyncDistance != "0" {
-			reason = "sync_distance_not_zero"
-		} else {
-			reason = "not_synced"
-		}
+// runProbes runs the node's probes concurrently and records their results.
+// Nodes that do not list their own probes use the defaults for cfg.
+func (bn *BeaconNode) runProbes(cfg config.HealthCheckConfig) []ProbeResult {
+	probes := bn.probes
+	if len(probes) == 0 {
+		probes = defaultProbes(cfg)
+	}
 
-		return false, &HealthCheckError{
-			Reason:       reason,
-			IsSyncing:    syncResp.Data.IsSyncing,
-			SyncDistance: syncResp.Data.SyncDistance,
+	// Create HTTP client with timeout
+	client := &http.Client{
+		Timeout: cfg.Timeout,
+	}
+
+	results := make([]ProbeResult, len(probes))
+	var wg sync.WaitGroup
+	for i, probe := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			healthErr := probe.Check(bn, client)
+			if healthErr != nil {
+				healthErr.Probe = probe.Name()
+			}
+			results[i] = ProbeResult{
+				Probe:   probe.Name(),
+				Healthy: healthErr == nil,
+				Error:   healthErr,
+			}
+		}()
+	}
+	wg.Wait()
+
+	bn.mu.Lock()
+	bn.probeResults = results
+	bn.LastCheck = time.Now()
+	bn.lastHealthErr = firstFailure(results)
+	bn.mu.Unlock()
+	return results
+}
+
+// firstFailure returns the error of the first failed probe, or nil if all passed
+func firstFailure(results []ProbeResult) *HealthCheckError {
+	for _, result := range results {
+		if !result.Healthy {
+			return result.Error
 		}
 	}
+	return nil
+}
 
-	logger.Debug("node health check passed",
-		"node_name", bn.Name,
-		"priority", bn.Priority,
-	)
+// LastHealthCheck returns when the node was last health checked and why that check failed,
+// or a nil error if it passed
+func (bn *BeaconNode) LastHealthCheck() (time.Time, *HealthCheckError) {
+	bn.mu.RLock()
+	defer bn.mu.RUnlock()
+	return bn.LastCheck, bn.lastHealthErr
+}
 
-	return true, nil
+// ProbeResults returns the result of each probe in the node's latest health check
+func (bn *BeaconNode) ProbeResults() []ProbeResult {
+	bn.mu.RLock()
+	defer bn.mu.RUnlock()
+	return append([]ProbeResult(nil), bn.probeResults...)
+}
+
+// evaluateSyncStatus checks each sync signal in turn and reports the first one that makes the node unhealthy
+func evaluateSyncStatus(syncResp *SyncingResponse, maxSyncDistance uint64) *HealthCheckError {
+	healthErr := &HealthCheckError{
+		IsSyncing:    syncResp.Data.IsSyncing,
+		SyncDistance: syncResp.Data.SyncDistance,
+		IsOptimistic: syncResp.Data.IsOptimistic,
+		ELOffline:    syncResp.Data.ELOffline,
+	}
+
+	syncDistance, err := strconv.ParseUint(syncResp.Data.SyncDistance, 10, 64)
+	switch {
+	case syncResp.Data.IsSyncing:
+		healthErr.Reason = "is_syncing"
+	case err != nil:
+		healthErr.Reason = "invalid_sync_distance"
+		healthErr.Err = err
+	case syncDistance > maxSyncDistance:
+		healthErr.Reason = "sync_distance_exceeded"
+	case syncResp.Data.IsOptimistic:
+		// An optimistic head has not been verified by the execution client
+		healthErr.Reason = "is_optimistic"
+	case syncResp.Data.ELOffline:
+		healthErr.Reason = "el_offline"
+	default:
+		return nil
+	}
+	return healthErr
 }
@@ -318,8 +318,8 @@ func TestHealthCheck_SyncDistanceNonZero(t *testing.T) {
 		t.Error("Expected HealthCheckError when sync_distance > 0")
 	}
 	if healthErr != nil {
-		if healthErr.Reason != "sync_distance_not_zero" {
-			t.Errorf("Expected reason 'sync_distance_not_zero', got: %s", healthErr.Reason)
+		if healthErr.Reason != "sync_distance_exceeded" {
+			t.Errorf("Expected reason 'sync_distance_exceeded', got: %s", healthErr.Reason)
 		}
 		if healthErr.IsSyncing {
 			t.Error("Expected IsSyncing to be false")
@@ -447,6 +447,109 @@ func TestHealthCheck_InvalidJSON(t *testing.T) {
 	}
 }
 
+// TestHealthCheck_SyncSignals tests that each sync signal fails the check with its own reason
+func TestHealthCheck_SyncSignals(t *testing.T) {
+	testCases := []struct {
+		name            string
+		body            string
+		maxSyncDistance uint64
+		expectedReason  string
+	}{
+		{"synced", `{"data":{"is_syncing":false,"sync_distance":"0","is_optimistic":false,"el_offline":false}}`, 0, ""},
+		{"distance within tolerance", `{"data":{"is_syncing":false,"sync_distance":"2"}}`, 2, ""},
+		{"distance beyond tolerance", `{"data":{"is_syncing":false,"sync_distance":"3"}}`, 2, "sync_distance_exceeded"},
+		{"invalid distance", `{"data":{"is_syncing":false,"sync_distance":"abc"}}`, 0, "invalid_sync_distance"},
+		{"optimistic", `{"data":{"is_syncing":false,"sync_distance":"0","is_optimistic":true}}`, 0, "is_optimistic"},
+		{"el offline", `{"data":{"is_syncing":false,"sync_distance":"0","el_offline":true}}`, 0, "el_offline"},
+		{"syncing wins over optimistic", `{"data":{"is_syncing":true,"sync_distance":"0","is_optimistic":true}}`, 0, "is_syncing"},
+	}
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			node, err := beaconnode.NewBeaconNode(config.NodeConfig{Name: "test-node", URL: server.URL, Type: "lighthouse"}, cfg)
+			if err != nil {
+				t.Fatalf("Failed to create beacon node: %v", err)
+			}
+
+			isHealthy, healthErr := node.HealthCheck(config.HealthCheckConfig{
+				Timeout:         5 * time.Second,
+				MaxSyncDistance: tc.maxSyncDistance,
+			})
+			if tc.expectedReason == "" {
+				if !isHealthy || healthErr != nil {
+					t.Errorf("Expected node to be healthy, got: %v", healthErr)
+				}
+				return
+			}
+			if isHealthy {
+				t.Error("Expected node to be unhealthy")
+			}
+			if healthErr == nil || healthErr.Reason != tc.expectedReason {
+				t.Errorf("Expected reason %q, got: %v", tc.expectedReason, healthErr)
+			}
+		})
+	}
+}
+
+// TestHealthCheck_NodeHealth tests that /eth/v1/node/health status codes are checked when enabled
+func TestHealthCheck_NodeHealth(t *testing.T) {
+	testCases := []struct {
+		name           string
+		status         int
+		checkHealth    bool
+		expectedReason string
+	}{
+		{"ready", http.StatusOK, true, ""},
+		{"syncing", http.StatusPartialContent, true, "node_health_syncing"},
+		{"unavailable", http.StatusServiceUnavailable, true, "node_health_unavailable"},
+		{"disabled", http.StatusServiceUnavailable, false, ""},
+	}
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/eth/v1/node/health" {
+					w.WriteHeader(tc.status)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+			}))
+			defer server.Close()
+
+			node, err := beaconnode.NewBeaconNode(config.NodeConfig{Name: "test-node", URL: server.URL, Type: "lighthouse"}, cfg)
+			if err != nil {
+				t.Fatalf("Failed to create beacon node: %v", err)
+			}
+
+			_, healthErr := node.HealthCheck(config.HealthCheckConfig{
+				Timeout:         5 * time.Second,
+				CheckNodeHealth: tc.checkHealth,
+			})
+			if tc.expectedReason == "" {
+				if healthErr != nil {
+					t.Errorf("Expected node to be healthy, got: %v", healthErr)
+				}
+				return
+			}
+			if healthErr == nil || healthErr.Reason != tc.expectedReason {
+				t.Errorf("Expected reason %q, got: %v", tc.expectedReason, healthErr)
+			}
+			if healthErr != nil && healthErr.StatusCode != tc.status {
+				t.Errorf("Expected status code %d, got: %d", tc.status, healthErr.StatusCode)
+			}
+		})
+	}
+}
+
 // TestHealthCheckError_Error tests the Error() method
 func TestHealthCheckError_Error(t *testing.T) {
 	// Test with underlying error
@@ -509,3 +612,59 @@ func TestHealthCheck_Timeout(t *testing.T) {
 		t.Errorf("HealthCheck took too long: %v (expected < 500ms due to timeout)", duration)
 	}
 }
+
+// TestFetchHead tests FetchHead parsing the head header and rejecting bad responses
+func TestFetchHead(t *testing.T) {
+	testCases := []struct {
+		name         string
+		status       int
+		body         string
+		expectedSlot uint64
+		expectedRoot string
+		expectError  bool
+	}{
+		{
+			name:         "valid head",
+			status:       http.StatusOK,
+			body:         `{"execution_optimistic":false,"finalized":false,"data":{"root":"0xabc","canonical":true,"header":{"message":{"slot":"12345","proposer_index":"1"},"signature":"0x00"}}}`,
+			expectedSlot: 12345,
+			expectedRoot: "0xabc",
+		},
+		{name: "non-200 status", status: http.StatusServiceUnavailable, body: `{}`, expectError: true},
+		{name: "invalid slot", status: http.StatusOK, body: `{"data":{"root":"0xabc","header":{"message":{"slot":"abc"}}}}`, expectError: true},
+		{name: "invalid json", status: http.StatusOK, body: `not json`, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/eth/v1/beacon/headers/head" {
+					t.Errorf("Unexpected path: %s", r.URL.Path)
+				}
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			cfg := config.LoadOrDefault("../../config.toml")
+			node, err := beaconnode.NewBeaconNode(config.NodeConfig{Name: "test-node", URL: server.URL, Type: "lighthouse"}, cfg)
+			if err != nil {
+				t.Fatalf("Failed to create beacon node: %v", err)
+			}
+
+			slot, root, err := node.FetchHead(5 * time.Second)
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchHead failed: %v", err)
+			}
+			if slot != tc.expectedSlot || root != tc.expectedRoot {
+				t.Errorf("Expected head %d/%s, got %d/%s", tc.expectedSlot, tc.expectedRoot, slot, root)
+			}
+		})
+	}
+}
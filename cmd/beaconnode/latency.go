@@ -0,0 +1,57 @@
+package beaconnode
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// latencyWindowSize is the number of recent request latencies kept per node
+	latencyWindowSize = 100
+	// minLatencySamples is the number of samples required before percentiles are reported
+	minLatencySamples = 20
+)
+
+// latencyWindow keeps a ring buffer of recent successful request latencies
+type latencyWindow struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+// RecordLatency records the latency of a successful request to the node
+func (bn *BeaconNode) RecordLatency(d time.Duration) {
+	bn.latency.mu.Lock()
+	defer bn.latency.mu.Unlock()
+
+	if len(bn.latency.samples) < latencyWindowSize {
+		bn.latency.samples = append(bn.latency.samples, d)
+		return
+	}
+	bn.latency.samples[bn.latency.next] = d
+	bn.latency.next = (bn.latency.next + 1) % latencyWindowSize
+}
+
+// LatencyPercentile returns the p-th percentile (0 < p <= 1) of recent request latencies.
+// Returns false until enough samples have been recorded to make the estimate meaningful.
+func (bn *BeaconNode) LatencyPercentile(p float64) (time.Duration, bool) {
+	bn.latency.mu.Lock()
+	samples := make([]time.Duration, len(bn.latency.samples))
+	copy(samples, bn.latency.samples)
+	bn.latency.mu.Unlock()
+
+	if len(samples) < minLatencySamples {
+		return 0, false
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	index := int(p*float64(len(samples))+0.5) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(samples) {
+		index = len(samples) - 1
+	}
+	return samples[index], true
+}
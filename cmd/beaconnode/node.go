@@ -1,6 +1,8 @@
 package beaconnode
 
 import (
+	"encoding/base64"
+	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"sync"
@@ -14,15 +16,33 @@ import (
 type BeaconNode struct {
 	Name                 string
 	URL                  string
+	Type                 string // beacon client type, used to look up its capabilities
 	Proxy                *httputil.ReverseProxy
 	ConsecutiveErrors    int64 // atomic consecutive error counter
 	ConsecutiveSuccesses int64 // atomic consecutive success counter (for failback)
 	TotalFailures        int64 // atomic total failure counter
+	FailedChecks         int64 // atomic consecutive failed health check counter (for primary demotion)
 	Requests             int64 // atomic request counter
-	LastCheck            time.Time
+	Outstanding          int64 // atomic counter of requests currently in flight
+	Streams              int64 // atomic counter of websocket and event streams currently open
+	Weight               int   // relative share of requests for weighted routing
+	Breaker              *CircuitBreaker
+	LastCheck            time.Time // time of the latest health check, guarded by mu
 	mu                   sync.RWMutex
 	Priority             int
 	OriginalPriority     int // The node's initial configured priority (never changes)
+	latency              latencyWindow
+	headSlot             uint64 // latest known head slot, guarded by mu
+	headRoot             string // latest known head block root, guarded by mu
+	headKnown            bool
+	forked               bool              // on a minority fork and quarantined from routing, guarded by mu
+	forkStrikes          int               // consecutive fork comparisons the node disagreed in, guarded by mu
+	cordoned             bool              // taken out of rotation by an operator, guarded by mu
+	draining             bool              // cordoned until its requests and streams finish, guarded by mu
+	probes               []Probe           // configured health check probes; empty means the defaults
+	probeResults         []ProbeResult     // results of the latest health check, guarded by mu
+	lastHealthErr        *HealthCheckError // first failure of the latest health check, guarded by mu
+	upstreamHeaders      http.Header       // configured credentials and headers sent with every request
 }
 
 // NewBeaconNode creates a new beacon node with reverse proxy
@@ -33,24 +53,96 @@ func NewBeaconNode(nodeConfig config.NodeConfig, cfg *config.Config) (*BeaconNod
 	}
 
 	proxy := httputil.NewSingleHostReverseProxy(parsedURL)
+	upstreamHeaders := newUpstreamHeaders(nodeConfig)
 
 	// Configure proxy Director with custom header handling
-	proxy.Director = createProxyDirector(proxy.Director, parsedURL, cfg.Proxy.UserAgent, nodeConfig.URL)
+	proxy.Director = createProxyDirector(proxy.Director, parsedURL, cfg.Proxy.UserAgent, nodeConfig.URL, upstreamHeaders)
 
 	// Configure proxy with optimized transport settings
 	proxy.Transport = createProxyTransport(cfg, nodeConfig.URL)
 
+	// Nodes without an explicit weight get an equal share
+	weight := nodeConfig.Weight
+	if weight < 1 {
+		weight = 1
+	}
+
+	probes := make([]Probe, 0, len(nodeConfig.Probes))
+	for _, probeConfig := range nodeConfig.Probes {
+		probe, err := newProbe(probeConfig, cfg.HealthCheck)
+		if err != nil {
+			return nil, err
+		}
+		probes = append(probes, probe)
+	}
+
 	node := &BeaconNode{
 		Name:              nodeConfig.Name,
 		URL:               nodeConfig.URL,
+		Type:              nodeConfig.Type,
 		Proxy:             proxy,
 		ConsecutiveErrors: 0, // start with no errors
+		Weight:            weight,
+		Breaker:           NewCircuitBreaker(cfg.Breaker),
 		LastCheck:         time.Now(),
+		probes:            probes,
+		upstreamHeaders:   upstreamHeaders,
 	}
 
 	return node, nil
 }
 
+// newUpstreamHeaders builds the headers carrying a node's configured credentials
+func newUpstreamHeaders(nodeConfig config.NodeConfig) http.Header {
+	header := make(http.Header, len(nodeConfig.Headers)+1)
+	for name, value := range nodeConfig.Headers {
+		header.Set(name, value.Value())
+	}
+	if nodeConfig.BasicAuth != nil {
+		credentials := nodeConfig.BasicAuth.Username + ":" + nodeConfig.BasicAuth.Password.Value()
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(credentials)))
+	}
+	if nodeConfig.BearerToken != "" {
+		header.Set("Authorization", "Bearer "+nodeConfig.BearerToken.Value())
+	}
+	return header
+}
+
+// SetUpstreamHeaders sets the node's configured credentials and headers on h, replacing any
+// the client sent under the same names
+func (bn *BeaconNode) SetUpstreamHeaders(h http.Header) {
+	for name, values := range bn.upstreamHeaders {
+		h[name] = values
+	}
+}
+
+// get sends a GET request for path to the node with its upstream headers
+func (bn *BeaconNode) get(client *http.Client, path string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, bn.URL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	bn.SetUpstreamHeaders(req.Header)
+	return client.Do(req)
+}
+
+// SettingsChanged reports whether the settings a node takes from the configuration when it is
+// created differ between two configurations, so that nodes must be recreated to pick them up
+func SettingsChanged(before, after *config.Config) bool {
+	return before.Proxy != after.Proxy ||
+		before.DNS != after.DNS ||
+		before.Breaker != after.Breaker ||
+		before.HealthCheck.SecondsPerSlot != after.HealthCheck.SecondsPerSlot ||
+		before.HealthCheck.SlotsPerEpoch != after.HealthCheck.SlotsPerEpoch
+}
+
+// CloseIdleConnections closes the node's idle upstream connections, once it is no longer used
+func (bn *BeaconNode) CloseIdleConnections() {
+	if transport, ok := bn.Proxy.Transport.(*http.Transport); ok {
+		transport.CloseIdleConnections()
+	}
+}
+
 // IsHealthy returns whether the node is currently healthy based on error threshold
 func (bn *BeaconNode) IsHealthy(errorThreshold int) bool {
 	return atomic.LoadInt64(&bn.ConsecutiveErrors) < int64(errorThreshold)
@@ -84,11 +176,87 @@ func (bn *BeaconNode) GetConsecutiveSuccesses() int64 {
 	return atomic.LoadInt64(&bn.ConsecutiveSuccesses)
 }
 
+// IncrementFailedChecks increments the consecutive failed health check count and returns the new count
+func (bn *BeaconNode) IncrementFailedChecks() int64 {
+	return atomic.AddInt64(&bn.FailedChecks, 1)
+}
+
+// ResetFailedChecks resets the consecutive failed health check count
+func (bn *BeaconNode) ResetFailedChecks() {
+	atomic.StoreInt64(&bn.FailedChecks, 0)
+}
+
 // IncrementRequests increments the request counter
 func (bn *BeaconNode) IncrementRequests() {
 	atomic.AddInt64(&bn.Requests, 1)
 }
 
+// BeginRequest marks a request to the node as in flight
+func (bn *BeaconNode) BeginRequest() {
+	atomic.AddInt64(&bn.Outstanding, 1)
+}
+
+// EndRequest marks an in-flight request to the node as finished
+func (bn *BeaconNode) EndRequest() {
+	atomic.AddInt64(&bn.Outstanding, -1)
+}
+
+// GetOutstanding returns the number of requests currently in flight to the node
+func (bn *BeaconNode) GetOutstanding() int64 {
+	return atomic.LoadInt64(&bn.Outstanding)
+}
+
+// BeginStream marks a websocket or event stream to the node as open
+func (bn *BeaconNode) BeginStream() {
+	atomic.AddInt64(&bn.Streams, 1)
+}
+
+// EndStream marks a websocket or event stream to the node as closed
+func (bn *BeaconNode) EndStream() {
+	atomic.AddInt64(&bn.Streams, -1)
+}
+
+// GetStreams returns the number of websocket and event streams currently open to the node
+func (bn *BeaconNode) GetStreams() int64 {
+	return atomic.LoadInt64(&bn.Streams)
+}
+
+// Cordon takes the node out of rotation so it receives no new requests. A draining node
+// is also expected to finish its in-flight requests and open streams (see IsDrained).
+func (bn *BeaconNode) Cordon(drain bool) {
+	bn.mu.Lock()
+	defer bn.mu.Unlock()
+	bn.cordoned = true
+	bn.draining = bn.draining || drain
+}
+
+// Uncordon puts a cordoned or draining node back into rotation
+func (bn *BeaconNode) Uncordon() {
+	bn.mu.Lock()
+	defer bn.mu.Unlock()
+	bn.cordoned = false
+	bn.draining = false
+}
+
+// IsCordoned returns whether the node has been taken out of rotation by an operator
+func (bn *BeaconNode) IsCordoned() bool {
+	bn.mu.RLock()
+	defer bn.mu.RUnlock()
+	return bn.cordoned
+}
+
+// IsDraining returns whether the node is being drained
+func (bn *BeaconNode) IsDraining() bool {
+	bn.mu.RLock()
+	defer bn.mu.RUnlock()
+	return bn.draining
+}
+
+// IsDrained returns whether a draining node has no requests or streams left
+func (bn *BeaconNode) IsDrained() bool {
+	return bn.IsDraining() && bn.GetOutstanding() == 0 && bn.GetStreams() == 0
+}
+
 // GetStats returns current node statistics
 func (bn *BeaconNode) GetStats() (consecutiveErrors int64, totalFailures int64, requests int64) {
 	return atomic.LoadInt64(&bn.ConsecutiveErrors), atomic.LoadInt64(&bn.TotalFailures), atomic.LoadInt64(&bn.Requests)
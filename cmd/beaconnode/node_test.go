@@ -1,7 +1,11 @@
 package beaconnode_test
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -287,3 +291,260 @@ func TestNewBeaconNode_Configuration(t *testing.T) {
 		t.Error("Expected LastCheck to be recent")
 	}
 }
+
+func TestNewBeaconNode_UpstreamCredentials(t *testing.T) {
+	testCases := []struct {
+		name          string
+		nodeConfig    config.NodeConfig
+		authorization string
+	}{
+		{"bearer token", config.NodeConfig{BearerToken: "token"}, "Bearer token"},
+		{"basic auth", config.NodeConfig{BasicAuth: &config.BasicAuthConfig{Username: "user", Password: "pass"}}, "Basic dXNlcjpwYXNz"},
+		{"authorization header", config.NodeConfig{Headers: map[string]config.Secret{"authorization": "ApiKey key"}}, "ApiKey key"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Every kind of request to the node must carry its credentials
+			seen := make(chan string, 4)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != tc.authorization || r.Header.Get("X-Api-Key") != "key" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				seen <- r.URL.Path
+				switch r.URL.Path {
+				case "/eth/v1/node/syncing":
+					w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+				case "/eth/v1/beacon/headers/head":
+					w.Write([]byte(`{"data":{"root":"0x01","header":{"message":{"slot":"1"}}}}`))
+				case "/eth/v1/events":
+					w.Header().Set("Content-Type", "text/event-stream")
+				default:
+					w.Write([]byte(`{}`))
+				}
+			}))
+			defer server.Close()
+
+			nodeConfig := tc.nodeConfig
+			nodeConfig.Name = "provider"
+			nodeConfig.URL = server.URL
+			if nodeConfig.Headers == nil {
+				nodeConfig.Headers = map[string]config.Secret{}
+			}
+			nodeConfig.Headers["X-Api-Key"] = "key"
+			node, err := beaconnode.NewBeaconNode(nodeConfig, config.LoadOrDefault("../../config.toml"))
+			if err != nil {
+				t.Fatalf("Failed to create beacon node: %v", err)
+			}
+
+			// Proxied requests replace whatever credentials the client sent
+			req := httptest.NewRequest("GET", "/eth/v1/node/version", nil)
+			req.Header.Set("Authorization", "Bearer client")
+			w := httptest.NewRecorder()
+			node.Proxy.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Errorf("Expected the proxied request to authenticate, got %d", w.Code)
+			}
+
+			if healthy, err := node.CheckSyncStatus(config.HealthCheckConfig{Timeout: time.Second}); !healthy {
+				t.Errorf("Expected the health check to authenticate: %v", err)
+			}
+			if _, _, err := node.FetchHead(time.Second); err != nil {
+				t.Errorf("Expected the head fetch to authenticate: %v", err)
+			}
+			resp, err := node.SubscribeEvents(context.Background(), []string{"head"})
+			if err != nil {
+				t.Errorf("Expected the event subscription to authenticate: %v", err)
+			} else {
+				resp.Body.Close()
+			}
+
+			header := http.Header{"Authorization": {"Bearer client"}}
+			node.SetUpstreamHeaders(header)
+			if header.Get("Authorization") != tc.authorization || header.Get("X-Api-Key") != "key" {
+				t.Errorf("Expected websocket dial headers to carry the credentials, got %v", header)
+			}
+			if len(seen) != 4 {
+				t.Errorf("Expected 4 authenticated requests, got %d", len(seen))
+			}
+		})
+	}
+}
+
+func TestNewBeaconNode_AcceptHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("Accept")))
+	}))
+	defer server.Close()
+
+	node, err := beaconnode.NewBeaconNode(config.NodeConfig{Name: "node", URL: server.URL}, config.LoadOrDefault("../../config.toml"))
+	if err != nil {
+		t.Fatalf("Failed to create beacon node: %v", err)
+	}
+
+	testCases := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{"missing", "", "application/json"},
+		{"ssz", "application/octet-stream", "application/octet-stream"},
+		{"ssz or json", "application/octet-stream;q=1.0,application/json;q=0.9", "application/octet-stream;q=1.0,application/json;q=0.9"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/eth/v2/beacon/blocks/head", nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			w := httptest.NewRecorder()
+			node.Proxy.ServeHTTP(w, req)
+			if got := w.Body.String(); got != tc.want {
+				t.Errorf("Expected the node to receive Accept %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestBeaconNode_LatencyPercentile(t *testing.T) {
+	node := &beaconnode.BeaconNode{Name: "test-node"}
+
+	// Too few samples to estimate a percentile
+	for i := 0; i < 10; i++ {
+		node.RecordLatency(10 * time.Millisecond)
+	}
+	if _, ok := node.LatencyPercentile(0.95); ok {
+		t.Error("Expected no percentile with only 10 samples")
+	}
+
+	// 1ms..100ms, so p95 is 95ms and p50 is 50ms
+	for i := 1; i <= 100; i++ {
+		node.RecordLatency(time.Duration(i) * time.Millisecond)
+	}
+	p95, ok := node.LatencyPercentile(0.95)
+	if !ok {
+		t.Fatal("Expected a percentile once the window is full")
+	}
+	if p95 != 95*time.Millisecond {
+		t.Errorf("Expected p95=95ms, got %v", p95)
+	}
+	if p50, _ := node.LatencyPercentile(0.5); p50 != 50*time.Millisecond {
+		t.Errorf("Expected p50=50ms, got %v", p50)
+	}
+
+	// Old samples are evicted as new ones arrive
+	for i := 0; i < 100; i++ {
+		node.RecordLatency(time.Second)
+	}
+	if p95, _ := node.LatencyPercentile(0.95); p95 != time.Second {
+		t.Errorf("Expected p95=1s after the window rolled over, got %v", p95)
+	}
+}
+
+func TestCircuitBreakerAdmitsOneProbe(t *testing.T) {
+	breaker := beaconnode.NewCircuitBreaker(config.BreakerConfig{
+		Enabled:            true,
+		WindowSize:         1,
+		MinRequests:        1,
+		ErrorRateThreshold: 0.5,
+		SlowRateThreshold:  1,
+		OpenDuration:       10 * time.Millisecond,
+		HalfOpenProbes:     1,
+	})
+	breaker.Record(time.Millisecond, true)
+	time.Sleep(20 * time.Millisecond)
+
+	// Requests racing for a half-open circuit get a single probe slot between them
+	var admitted atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if breaker.TryAcquire() {
+				admitted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := admitted.Load(); got != 1 {
+		t.Fatalf("Expected exactly one probe to be admitted, got %d", got)
+	}
+
+	// An abandoned probe frees the slot for the next request
+	breaker.Abandon()
+	if !breaker.TryAcquire() {
+		t.Error("Expected a probe to be admitted after the previous one was abandoned")
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	breaker := beaconnode.NewCircuitBreaker(config.BreakerConfig{
+		Enabled:            true,
+		WindowSize:         4,
+		MinRequests:        4,
+		ErrorRateThreshold: 0.5,
+		SlowCallDuration:   time.Second,
+		SlowRateThreshold:  0.75,
+		OpenDuration:       50 * time.Millisecond,
+		HalfOpenProbes:     2,
+	})
+
+	var transitions []string
+	breaker.OnStateChange(func(from, to beaconnode.CircuitState) {
+		transitions = append(transitions, from.String()+"->"+to.String())
+	})
+
+	// Below min_requests the circuit stays closed whatever the error rate
+	breaker.Record(time.Millisecond, true)
+	breaker.Record(time.Millisecond, true)
+	breaker.Record(time.Millisecond, false)
+	if breaker.State() != beaconnode.CircuitClosed || !breaker.Available() {
+		t.Fatalf("Expected a closed circuit below min_requests, got %s", breaker.State())
+	}
+
+	// Half of the window failing opens it
+	breaker.Record(time.Millisecond, false)
+	if breaker.State() != beaconnode.CircuitOpen || breaker.TryAcquire() {
+		t.Fatalf("Expected an open circuit at the error rate threshold, got %s", breaker.State())
+	}
+
+	// After open_duration a single probe is let through at a time
+	time.Sleep(60 * time.Millisecond)
+	if !breaker.TryAcquire() || breaker.State() != beaconnode.CircuitHalfOpen {
+		t.Fatalf("Expected a half-open circuit after open_duration, got %s", breaker.State())
+	}
+	if breaker.Available() || breaker.TryAcquire() {
+		t.Error("Expected no second probe while one is in flight")
+	}
+
+	// A slow probe opens the circuit again
+	breaker.Record(2*time.Second, false)
+	if breaker.State() != beaconnode.CircuitOpen {
+		t.Fatalf("Expected a slow probe to reopen the circuit, got %s", breaker.State())
+	}
+
+	// Enough successful probes close it
+	time.Sleep(60 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if !breaker.TryAcquire() {
+			t.Fatalf("Expected probe %d to be allowed", i+1)
+		}
+		breaker.Record(time.Millisecond, false)
+	}
+	if breaker.State() != beaconnode.CircuitClosed {
+		t.Fatalf("Expected successful probes to close the circuit, got %s", breaker.State())
+	}
+
+	expected := []string{"closed->open", "open->half_open", "half_open->open", "open->half_open", "half_open->closed"}
+	if len(transitions) != len(expected) {
+		t.Fatalf("Expected transitions %v, got %v", expected, transitions)
+	}
+	for i := range expected {
+		if transitions[i] != expected[i] {
+			t.Errorf("Expected transition %d to be %s, got %s", i, expected[i], transitions[i])
+		}
+	}
+}
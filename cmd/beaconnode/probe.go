@@ -0,0 +1,307 @@
+package beaconnode
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+// Probe checks one aspect of a beacon node's health
+type Probe interface {
+	// Name returns the probe type as used in the configuration
+	Name() string
+	// Check probes the node and returns nil if it is healthy in this respect
+	Check(bn *BeaconNode, client *http.Client) *HealthCheckError
+}
+
+// ProbeResult is the outcome of one probe in a node's latest health check
+type ProbeResult struct {
+	Probe   string
+	Healthy bool
+	Error   *HealthCheckError // nil if the probe passed
+}
+
+// newProbe creates the probe described by cfg
+func newProbe(cfg config.ProbeConfig, healthCfg config.HealthCheckConfig) (Probe, error) {
+	switch cfg.Type {
+	case config.ProbeSyncing:
+		return syncingProbe{maxSyncDistance: cfg.MaxSyncDistance}, nil
+	case config.ProbeNodeHealth:
+		return nodeHealthProbe{}, nil
+	case config.ProbePeerCount:
+		return peerCountProbe{minPeers: cfg.MinPeers}, nil
+	case config.ProbeHeadFreshness:
+		return &headFreshnessProbe{
+			maxSlotLag:     cfg.MaxSlotLag,
+			secondsPerSlot: healthCfg.SecondsPerSlot,
+			now:            time.Now,
+		}, nil
+	case config.ProbeFinalityLag:
+		return finalityLagProbe{
+			maxEpochLag:   cfg.MaxEpochLag,
+			slotsPerEpoch: healthCfg.SlotsPerEpoch,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown probe type: %s", cfg.Type)
+	}
+}
+
+// defaultProbes returns the probes used by nodes that do not list their own:
+// syncing, plus node_health if check_node_health is set
+func defaultProbes(cfg config.HealthCheckConfig) []Probe {
+	probes := []Probe{syncingProbe{maxSyncDistance: cfg.MaxSyncDistance}}
+	if cfg.CheckNodeHealth {
+		probes = append(probes, nodeHealthProbe{})
+	}
+	return probes
+}
+
+// getJSON fetches path from the node and decodes its JSON body into v
+func (bn *BeaconNode) getJSON(client *http.Client, path string, v any) *HealthCheckError {
+	resp, err := bn.get(client, path)
+	if err != nil {
+		return &HealthCheckError{
+			Reason: "request_failed",
+			Err:    err,
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &HealthCheckError{
+			Reason:     "non_200_status",
+			StatusCode: resp.StatusCode,
+		}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &HealthCheckError{
+			Reason: "read_body_failed",
+			Err:    err,
+		}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return &HealthCheckError{
+			Reason: "json_parse_failed",
+			Err:    err,
+		}
+	}
+	return nil
+}
+
+// syncingProbe requires `/eth/v1/node/syncing` to report a synced, non-optimistic node whose
+// execution client is online and whose sync distance is within maxSyncDistance
+type syncingProbe struct {
+	maxSyncDistance uint64
+}
+
+func (syncingProbe) Name() string { return config.ProbeSyncing }
+
+func (p syncingProbe) Check(bn *BeaconNode, client *http.Client) *HealthCheckError {
+	var syncResp SyncingResponse
+	if healthErr := bn.getJSON(client, "/eth/v1/node/syncing", &syncResp); healthErr != nil {
+		return healthErr
+	}
+	return evaluateSyncStatus(&syncResp, p.maxSyncDistance)
+}
+
+// nodeHealthProbe requires `/eth/v1/node/health` to answer 200. It answers 206 while the node
+// is syncing, and 503 when it is not initialized or having issues.
+type nodeHealthProbe struct{}
+
+func (nodeHealthProbe) Name() string { return config.ProbeNodeHealth }
+
+func (nodeHealthProbe) Check(bn *BeaconNode, client *http.Client) *HealthCheckError {
+	resp, err := bn.get(client, "/eth/v1/node/health")
+	if err != nil {
+		return &HealthCheckError{
+			Reason: "request_failed",
+			Err:    err,
+		}
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusPartialContent:
+		return &HealthCheckError{
+			Reason:     "node_health_syncing",
+			StatusCode: resp.StatusCode,
+		}
+	default:
+		return &HealthCheckError{
+			Reason:     "node_health_unavailable",
+			StatusCode: resp.StatusCode,
+		}
+	}
+}
+
+// PeerCountResponse represents the response from /eth/v1/node/peer_count endpoint
+type PeerCountResponse struct {
+	Data struct {
+		Connected string `json:"connected"`
+	} `json:"data"`
+}
+
+// peerCountProbe requires the node to have at least minPeers connected peers
+type peerCountProbe struct {
+	minPeers int
+}
+
+func (peerCountProbe) Name() string { return config.ProbePeerCount }
+
+func (p peerCountProbe) Check(bn *BeaconNode, client *http.Client) *HealthCheckError {
+	var peerResp PeerCountResponse
+	if healthErr := bn.getJSON(client, "/eth/v1/node/peer_count", &peerResp); healthErr != nil {
+		return healthErr
+	}
+
+	connected, err := strconv.Atoi(peerResp.Data.Connected)
+	if err != nil {
+		return &HealthCheckError{
+			Reason: "json_parse_failed",
+			Err:    fmt.Errorf("invalid peer count %q: %w", peerResp.Data.Connected, err),
+		}
+	}
+	if connected < p.minPeers {
+		return &HealthCheckError{
+			Reason: "peer_count_low",
+			Err:    fmt.Errorf("%d connected peers, need at least %d", connected, p.minPeers),
+		}
+	}
+	return nil
+}
+
+// GenesisResponse represents the response from /eth/v1/beacon/genesis endpoint
+type GenesisResponse struct {
+	Data struct {
+		GenesisTime string `json:"genesis_time"`
+	} `json:"data"`
+}
+
+// headFreshnessProbe requires the node's head to be within maxSlotLag of the current wall-clock
+// slot, computed from the chain's genesis time. Unlike head tracking, which compares nodes with
+// each other, this catches every node being stuck at once, so maxSlotLag must stay well above
+// the run of missed slots the chain may see or the whole pool fails together.
+type headFreshnessProbe struct {
+	maxSlotLag     uint64
+	secondsPerSlot int
+	now            func() time.Time
+
+	mu          sync.Mutex
+	genesisTime time.Time // fetched once from the node, zero until then
+}
+
+func (*headFreshnessProbe) Name() string { return config.ProbeHeadFreshness }
+
+func (p *headFreshnessProbe) Check(bn *BeaconNode, client *http.Client) *HealthCheckError {
+	genesisTime, healthErr := p.genesis(bn, client)
+	if healthErr != nil {
+		return healthErr
+	}
+
+	headSlot, _, err := bn.FetchHead(client.Timeout)
+	if err != nil {
+		return &HealthCheckError{
+			Reason: "request_failed",
+			Err:    err,
+		}
+	}
+
+	elapsed := p.now().Sub(genesisTime)
+	if elapsed < 0 {
+		return nil
+	}
+	currentSlot := uint64(elapsed / (time.Duration(p.secondsPerSlot) * time.Second))
+	if currentSlot > headSlot && currentSlot-headSlot > p.maxSlotLag {
+		return &HealthCheckError{
+			Reason: "head_stale",
+			Err:    fmt.Errorf("head slot %d is %d slots behind wall-clock slot %d", headSlot, currentSlot-headSlot, currentSlot),
+		}
+	}
+	return nil
+}
+
+// genesis returns the chain's genesis time, fetching it from the node the first time
+func (p *headFreshnessProbe) genesis(bn *BeaconNode, client *http.Client) (time.Time, *HealthCheckError) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.genesisTime.IsZero() {
+		return p.genesisTime, nil
+	}
+
+	var genesisResp GenesisResponse
+	if healthErr := bn.getJSON(client, "/eth/v1/beacon/genesis", &genesisResp); healthErr != nil {
+		return time.Time{}, healthErr
+	}
+	seconds, err := strconv.ParseInt(genesisResp.Data.GenesisTime, 10, 64)
+	if err != nil {
+		return time.Time{}, &HealthCheckError{
+			Reason: "json_parse_failed",
+			Err:    fmt.Errorf("invalid genesis time %q: %w", genesisResp.Data.GenesisTime, err),
+		}
+	}
+	p.genesisTime = time.Unix(seconds, 0)
+	return p.genesisTime, nil
+}
+
+// FinalityCheckpointsResponse represents the response from
+// /eth/v1/beacon/states/{state_id}/finality_checkpoints endpoint
+type FinalityCheckpointsResponse struct {
+	Data struct {
+		Finalized struct {
+			Epoch string `json:"epoch"`
+			Root  string `json:"root"`
+		} `json:"finalized"`
+	} `json:"data"`
+}
+
+// finalityLagProbe requires the node's finalized checkpoint to be within maxEpochLag epochs
+// of its head. A healthy chain finalizes two epochs behind the head.
+type finalityLagProbe struct {
+	maxEpochLag   uint64
+	slotsPerEpoch int
+}
+
+func (finalityLagProbe) Name() string { return config.ProbeFinalityLag }
+
+func (p finalityLagProbe) Check(bn *BeaconNode, client *http.Client) *HealthCheckError {
+	headSlot, _, err := bn.FetchHead(client.Timeout)
+	if err != nil {
+		return &HealthCheckError{
+			Reason: "request_failed",
+			Err:    err,
+		}
+	}
+
+	var finalityResp FinalityCheckpointsResponse
+	if healthErr := bn.getJSON(client, "/eth/v1/beacon/states/head/finality_checkpoints", &finalityResp); healthErr != nil {
+		return healthErr
+	}
+	finalizedEpoch, err := strconv.ParseUint(finalityResp.Data.Finalized.Epoch, 10, 64)
+	if err != nil {
+		return &HealthCheckError{
+			Reason: "json_parse_failed",
+			Err:    fmt.Errorf("invalid finalized epoch %q: %w", finalityResp.Data.Finalized.Epoch, err),
+		}
+	}
+
+	headEpoch := headSlot / uint64(p.slotsPerEpoch)
+	if headEpoch > finalizedEpoch && headEpoch-finalizedEpoch > p.maxEpochLag {
+		return &HealthCheckError{
+			Reason: "finality_lag_exceeded",
+			Err:    fmt.Errorf("finalized epoch %d is %d epochs behind head epoch %d", finalizedEpoch, headEpoch-finalizedEpoch, headEpoch),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package beaconnode_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+// newProbeServer creates a beacon node answering the endpoints used by the built-in probes
+func newProbeServer(peers string, headSlot uint64, genesisTime time.Time, finalizedEpoch uint64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/node/syncing":
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+		case "/eth/v1/node/peer_count":
+			w.Write([]byte(`{"data":{"connected":"` + peers + `"}}`))
+		case "/eth/v1/beacon/headers/head":
+			w.Write([]byte(`{"data":{"root":"0xabc","header":{"message":{"slot":"` + strconv.FormatUint(headSlot, 10) + `"}}}}`))
+		case "/eth/v1/beacon/genesis":
+			w.Write([]byte(`{"data":{"genesis_time":"` + strconv.FormatInt(genesisTime.Unix(), 10) + `"}}`))
+		case "/eth/v1/beacon/states/head/finality_checkpoints":
+			w.Write([]byte(`{"data":{"finalized":{"epoch":"` + strconv.FormatUint(finalizedEpoch, 10) + `"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+// TestHealthCheck_Probes tests that each built-in probe fails with its own reason
+func TestHealthCheck_Probes(t *testing.T) {
+	// Genesis 1000 slots ago, so the wall-clock slot is 1000
+	genesis := time.Now().Add(-1000 * 12 * time.Second)
+
+	testCases := []struct {
+		name           string
+		probe          config.ProbeConfig
+		peers          string
+		headSlot       uint64
+		finalizedEpoch uint64
+		expectedReason string
+	}{
+		{"enough peers", config.ProbeConfig{Type: config.ProbePeerCount, MinPeers: 8}, "8", 1000, 29, ""},
+		{"too few peers", config.ProbeConfig{Type: config.ProbePeerCount, MinPeers: 8}, "5", 1000, 29, "peer_count_low"},
+		{"fresh head", config.ProbeConfig{Type: config.ProbeHeadFreshness, MaxSlotLag: 2}, "8", 998, 29, ""},
+		{"stale head", config.ProbeConfig{Type: config.ProbeHeadFreshness, MaxSlotLag: 2}, "8", 990, 29, "head_stale"},
+		{"recent finality", config.ProbeConfig{Type: config.ProbeFinalityLag, MaxEpochLag: 4}, "8", 1000, 29, ""},
+		{"stalled finality", config.ProbeConfig{Type: config.ProbeFinalityLag, MaxEpochLag: 4}, "8", 1000, 20, "finality_lag_exceeded"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newProbeServer(tc.peers, tc.headSlot, genesis, tc.finalizedEpoch)
+			defer server.Close()
+
+			cfg := config.LoadOrDefault("../../config.toml")
+			nodeConfig := config.NodeConfig{
+				Name:   "test-node",
+				URL:    server.URL,
+				Type:   "lighthouse",
+				Probes: []config.ProbeConfig{tc.probe},
+			}
+
+			node, err := beaconnode.NewBeaconNode(nodeConfig, cfg)
+			if err != nil {
+				t.Fatalf("Failed to create beacon node: %v", err)
+			}
+
+			isHealthy, healthErr := node.HealthCheck(cfg.HealthCheck)
+			if tc.expectedReason == "" {
+				if !isHealthy || healthErr != nil {
+					t.Errorf("Expected node to be healthy, got: %v", healthErr)
+				}
+				return
+			}
+			if isHealthy {
+				t.Error("Expected node to be unhealthy")
+			}
+			if healthErr == nil || healthErr.Reason != tc.expectedReason {
+				t.Fatalf("Expected reason %q, got: %v", tc.expectedReason, healthErr)
+			}
+			if healthErr.Probe != tc.probe.Type {
+				t.Errorf("Expected probe %q, got: %q", tc.probe.Type, healthErr.Probe)
+			}
+		})
+	}
+}
+
+// TestHealthCheck_ProbeResults tests that every probe's result is kept, not just the first failure
+func TestHealthCheck_ProbeResults(t *testing.T) {
+	server := newProbeServer("2", 1000, time.Now().Add(-1000*12*time.Second), 20)
+	defer server.Close()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	nodeConfig := config.NodeConfig{
+		Name: "test-node",
+		URL:  server.URL,
+		Type: "lighthouse",
+		Probes: []config.ProbeConfig{
+			{Type: config.ProbeSyncing},
+			{Type: config.ProbePeerCount, MinPeers: 8},
+			{Type: config.ProbeFinalityLag, MaxEpochLag: 4},
+		},
+	}
+
+	node, err := beaconnode.NewBeaconNode(nodeConfig, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create beacon node: %v", err)
+	}
+
+	if len(node.ProbeResults()) != 0 {
+		t.Error("Expected no probe results before the first health check")
+	}
+
+	_, healthErr := node.HealthCheck(cfg.HealthCheck)
+	if healthErr == nil || healthErr.Probe != config.ProbePeerCount {
+		t.Errorf("Expected the first failing probe to be peer_count, got: %v", healthErr)
+	}
+
+	results := node.ProbeResults()
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 probe results, got %d", len(results))
+	}
+	expected := []struct {
+		probe   string
+		healthy bool
+	}{
+		{config.ProbeSyncing, true},
+		{config.ProbePeerCount, false},
+		{config.ProbeFinalityLag, false},
+	}
+	for i, want := range expected {
+		if results[i].Probe != want.probe || results[i].Healthy != want.healthy {
+			t.Errorf("Result %d: expected %s healthy=%t, got %s healthy=%t", i, want.probe, want.healthy, results[i].Probe, results[i].Healthy)
+		}
+	}
+}
@@ -10,7 +10,7 @@ import (
 
 // createProxyDirector creates a custom Director function for the reverse proxy
 // that properly handles headers for API providers like Chainstack
-func createProxyDirector(originalDirector func(*http.Request), targetURL *url.URL, userAgent, nodeURL string) func(*http.Request) {
+func createProxyDirector(originalDirector func(*http.Request), targetURL *url.URL, userAgent, nodeURL string, upstreamHeaders http.Header) func(*http.Request) {
 	return func(req *http.Request) {
 		originalDirector(req)
 
@@ -22,8 +22,10 @@ func createProxyDirector(originalDirector func(*http.Request), targetURL *url.UR
 		// Ensure Host header is set correctly for API providers
 		req.Host = targetURL.Host
 
-		// Add headers that some API providers expect
-		req.Header.Set("Accept", "application/json")
+		// Add headers that some API providers expect, keeping the media types the client asked for
+		if req.Header.Get("Accept") == "" {
+			req.Header.Set("Accept", "application/json")
+		}
 		if req.Header.Get("Content-Type") == "" && (req.Method == "POST" || req.Method == "PUT") {
 			req.Header.Set("Content-Type", "application/json")
 		}
@@ -44,6 +46,11 @@ func createProxyDirector(originalDirector func(*http.Request), targetURL *url.UR
 		if req.Header.Get("Connection") == "close" {
 			req.Header.Del("Connection")
 		}
+
+		// Authenticate with the node's configured credentials
+		for name, values := range upstreamHeaders {
+			req.Header[name] = values
+		}
 	}
 }
 
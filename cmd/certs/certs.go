@@ -0,0 +1,107 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+	"github.com/zircuit-labs/consensus-proxy/cmd/logger"
+)
+
+// Store holds the proxy's TLS certificate and client CA pool, and reloads them from their
+// files when they are rotated so that new connections use them without a restart
+type Store struct {
+	cfg       config.TLSConfig
+	cert      atomic.Pointer[tls.Certificate]
+	clientCAs atomic.Pointer[x509.CertPool]
+}
+
+// NewStore loads the certificate, key and client CA files named in cfg
+func NewStore(cfg config.TLSConfig) (*Store, error) {
+	s := &Store{cfg: cfg}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload reads the certificate files again. If any of them cannot be loaded, the ones in use
+// are kept and an error is returned.
+func (s *Store) Reload() error {
+	cert, err := tls.LoadX509KeyPair(s.cfg.CertFile, s.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %v", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if s.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(s.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read TLS client CA file: %v", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in TLS client CA file %s", s.cfg.ClientCAFile)
+		}
+	}
+
+	s.cert.Store(&cert)
+	s.clientCAs.Store(clientCAs)
+	return nil
+}
+
+// Watch reloads the certificate files whenever one of them changes, checking every
+// reload_interval until ctx is done
+func (s *Store) Watch(ctx context.Context) {
+	paths := []string{s.cfg.CertFile, s.cfg.KeyFile}
+	if s.cfg.ClientCAFile != "" {
+		paths = append(paths, s.cfg.ClientCAFile)
+	}
+
+	for _, path := range paths {
+		go config.Watch(ctx, path, s.cfg.ReloadInterval, func() {
+			// A certificate and key rotated one after the other do not match in between,
+			// so a failed reload keeps the current files until the next change
+			if err := s.Reload(); err != nil {
+				logger.Warn("TLS certificate reload failed - keeping the current certificate",
+					"path", path,
+					"error", err,
+				)
+				return
+			}
+			logger.Info("TLS certificate reloaded",
+				"path", path,
+			)
+		})
+	}
+}
+
+// TLSConfig returns a server TLS configuration that always uses the latest certificate and,
+// with a client CA file, requires clients to present a certificate signed by the latest CAs
+func (s *Store) TLSConfig() *tls.Config {
+	base := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2", "http/1.1"},
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return s.cert.Load(), nil
+		},
+	}
+	if s.cfg.MinVersion == config.TLSVersion13 {
+		base.MinVersion = tls.VersionTLS13
+	}
+
+	if s.cfg.ClientCAFile != "" {
+		base.ClientAuth = tls.RequireAndVerifyClientCert
+		base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cfg := base.Clone()
+			cfg.GetConfigForClient = nil
+			cfg.ClientCAs = s.clientCAs.Load()
+			return cfg, nil
+		}
+	}
+	return base
+}
@@ -0,0 +1,229 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+// testCert is a certificate with its key, signed by parent or self-signed if parent is nil
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newTestCert(t *testing.T, name string, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// writeCert writes a certificate and its key to the files named in cfg
+func writeCert(t *testing.T, cfg config.TLSConfig, cert *testCert) {
+	t.Helper()
+	if err := os.WriteFile(cfg.CertFile, cert.certPEM, 0o600); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	if err := os.WriteFile(cfg.KeyFile, cert.keyPEM, 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+}
+
+func testTLSConfig(t *testing.T) config.TLSConfig {
+	dir := t.TempDir()
+	return config.TLSConfig{
+		Enabled:        true,
+		CertFile:       filepath.Join(dir, "tls.crt"),
+		KeyFile:        filepath.Join(dir, "tls.key"),
+		MinVersion:     config.TLSVersion12,
+		ReloadInterval: 10 * time.Millisecond,
+	}
+}
+
+// servedCert returns the certificate a TLS server presents
+func servedCert(t *testing.T, server *httptest.Server) *x509.Certificate {
+	t.Helper()
+	conn, err := tls.Dial("tcp", server.Listener.Addr().String(), &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatalf("TLS dial failed: %v", err)
+	}
+	defer conn.Close()
+	return conn.ConnectionState().PeerCertificates[0]
+}
+
+// newTLSServer serves with tlsConfig alone, as httptest's own certificate would take
+// precedence over GetCertificate for clients that send no server name
+func newTLSServer(t *testing.T, tlsConfig *tls.Config) *httptest.Server {
+	t.Helper()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	server.Listener = tls.NewListener(server.Listener, tlsConfig)
+	server.Start()
+	server.URL = strings.Replace(server.URL, "http://", "https://", 1)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestStore_ReloadsRotatedCertificate(t *testing.T) {
+	cfg := testTLSConfig(t)
+	ca := newTestCert(t, "ca", nil)
+	first := newTestCert(t, "first", ca)
+	writeCert(t, cfg, first)
+
+	store, err := NewStore(cfg)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	server := newTLSServer(t, store.TLSConfig())
+	go store.Watch(t.Context())
+
+	if got := servedCert(t, server).Subject.CommonName; got != "first" {
+		t.Fatalf("Expected the first certificate, got %q", got)
+	}
+
+	// A key that does not match the certificate is rejected and the current one kept
+	second := newTestCert(t, "second", ca)
+	if err := os.WriteFile(cfg.CertFile, second.certPEM, 0o600); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	if err := store.Reload(); err == nil {
+		t.Fatal("Expected a mismatched certificate and key to be rejected")
+	}
+	if got := servedCert(t, server).Subject.CommonName; got != "first" {
+		t.Fatalf("Expected the first certificate to be kept, got %q", got)
+	}
+
+	// Once both files are rotated, new connections get the new certificate
+	writeCert(t, cfg, second)
+	deadline := time.Now().Add(2 * time.Second)
+	for servedCert(t, server).Subject.CommonName != "second" {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the rotated certificate to be served")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStore_RequiresClientCertificate(t *testing.T) {
+	cfg := testTLSConfig(t)
+	ca := newTestCert(t, "ca", nil)
+	writeCert(t, cfg, newTestCert(t, "server", ca))
+	cfg.ClientCAFile = filepath.Join(filepath.Dir(cfg.CertFile), "client-ca.crt")
+	if err := os.WriteFile(cfg.ClientCAFile, ca.certPEM, 0o600); err != nil {
+		t.Fatalf("Failed to write client CA: %v", err)
+	}
+	cfg.MinVersion = config.TLSVersion13
+
+	store, err := NewStore(cfg)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	server := newTLSServer(t, store.TLSConfig())
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	client := newTestCert(t, "client", ca)
+	clientPair, err := tls.X509KeyPair(client.certPEM, client.keyPEM)
+	if err != nil {
+		t.Fatalf("Failed to load client certificate: %v", err)
+	}
+	stranger := newTestCert(t, "stranger", newTestCert(t, "other-ca", nil))
+	strangerPair, err := tls.X509KeyPair(stranger.certPEM, stranger.keyPEM)
+	if err != nil {
+		t.Fatalf("Failed to load client certificate: %v", err)
+	}
+
+	testCases := []struct {
+		name         string
+		certificates []tls.Certificate
+		maxVersion   uint16
+		wantErr      bool
+	}{
+		{"signed client certificate", []tls.Certificate{clientPair}, 0, false},
+		{"no client certificate", nil, 0, true},
+		{"client certificate from another CA", []tls.Certificate{strangerPair}, 0, true},
+		{"below min_version", []tls.Certificate{clientPair}, tls.VersionTLS12, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+				RootCAs:      roots,
+				Certificates: tc.certificates,
+				MaxVersion:   tc.maxVersion,
+			}}}
+			resp, err := httpClient.Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if tc.wantErr != (err != nil) {
+				t.Errorf("Expected error=%v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNewStore_InvalidFiles(t *testing.T) {
+	cfg := testTLSConfig(t)
+	if _, err := NewStore(cfg); err == nil {
+		t.Error("Expected an error for missing certificate files")
+	}
+
+	writeCert(t, cfg, newTestCert(t, "server", nil))
+	cfg.ClientCAFile = cfg.KeyFile
+	if _, err := NewStore(cfg); err == nil {
+		t.Error("Expected an error for a client CA file without certificates")
+	}
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/BurntSushi/toml"
+)
+
+// AuthConfig contains configuration for authenticating clients with API keys. Keys come from
+// the config file and from keys_file, which are both read again on every reload.
+type AuthConfig struct {
+	Enabled    bool           `toml:"enabled"`
+	Header     string         `toml:"header"`      // Header carrying the key; an Authorization header takes "Bearer <key>"
+	QueryParam string         `toml:"query_param"` // Query parameter carrying the key, for clients that cannot set headers; empty disables it
+	KeysFile   string         `toml:"keys_file"`   // TOML file with more [[keys]], watched for changes
+	Keys       []APIKeyConfig `toml:"keys"`
+}
+
+// APIKeyConfig configures one client API key and what it may do
+type APIKeyConfig struct {
+	Name      string   `toml:"name"`       // Identifies the key in logs and metrics
+	Key       Secret   `toml:"key"`        // The key itself, or env:NAME or file:/path
+	Endpoints []string `toml:"endpoints"`  // Regex patterns of the paths the key may request; empty allows every endpoint
+	RateLimit int      `toml:"rate_limit"` // Requests per second allowed for the key; 0 disables the per-key limit
+	Nodes     []string `toml:"nodes"`      // Beacon names that may serve the key's requests; empty allows every node
+}
+
+// keysFile is the layout of auth keys_file
+type keysFile struct {
+	Keys []APIKeyConfig `toml:"keys"`
+}
+
+// loadAPIKeys resolves the secrets of the configured API keys and appends the keys from keys_file
+func (c *Config) loadAPIKeys() error {
+	if !c.Auth.Enabled {
+		return nil
+	}
+
+	if c.Auth.KeysFile != "" {
+		var file keysFile
+		if _, err := toml.DecodeFile(c.Auth.KeysFile, &file); err != nil {
+			return fmt.Errorf("failed to decode auth keys_file %s: %v", c.Auth.KeysFile, err)
+		}
+		c.Auth.Keys = append(c.Auth.Keys, file.Keys...)
+	}
+
+	for i, key := range c.Auth.Keys {
+		secret, err := resolveSecret(key.Key.Value())
+		if err != nil {
+			return fmt.Errorf("auth key %s: %v", key.Name, err)
+		}
+		c.Auth.Keys[i].Key = secret
+	}
+	return nil
+}
+
+// validateAuth checks that clients have a way to present a key, and that every key is unique,
+// has valid endpoint patterns and only refers to configured beacons
+func (c *Config) validateAuth() error {
+	if !c.Auth.Enabled {
+		return nil
+	}
+	if c.Auth.Header == "" && c.Auth.QueryParam == "" {
+		return fmt.Errorf("auth requires a header or query_param to read keys from")
+	}
+	if c.Auth.Header != "" && !validHeaderName(c.Auth.Header) {
+		return fmt.Errorf("invalid auth header name %q", c.Auth.Header)
+	}
+	if len(c.Auth.Keys) == 0 {
+		return fmt.Errorf("auth requires at least one key")
+	}
+
+	beacons := make(map[string]bool, len(c.Beacons.Nodes))
+	for _, name := range c.Beacons.Nodes {
+		beacons[name] = true
+	}
+
+	names := make(map[string]bool, len(c.Auth.Keys))
+	keys := make(map[Secret]bool, len(c.Auth.Keys))
+	for _, key := range c.Auth.Keys {
+		if key.Name == "" {
+			return fmt.Errorf("auth key name is required")
+		}
+		if names[key.Name] {
+			return fmt.Errorf("duplicate auth key name: %s", key.Name)
+		}
+		names[key.Name] = true
+
+		if key.Key == "" {
+			return fmt.Errorf("auth key %s: key is required", key.Name)
+		}
+		if keys[key.Key] {
+			return fmt.Errorf("auth key %s: key is already used by another key", key.Name)
+		}
+		keys[key.Key] = true
+
+		for _, pattern := range key.Endpoints {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("auth key %s: invalid endpoint pattern '%s': %v", key.Name, pattern, err)
+			}
+		}
+		if key.RateLimit < 0 {
+			return fmt.Errorf("auth key %s: rate_limit must not be negative", key.Name)
+		}
+		for _, node := range key.Nodes {
+			if !beacons[node] {
+				return fmt.Errorf("auth key %s: unknown beacon node: %s", key.Name, node)
+			}
+		}
+	}
+	return nil
+}
+
+// IsAuthorizationHeader reports whether the auth header is Authorization, which carries keys
+// as "Bearer <key>"
+func (a AuthConfig) IsAuthorizationHeader() bool {
+	return http.CanonicalHeaderKey(a.Header) == "Authorization"
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const authTestBeacons = `
+[beacons]
+nodes = ["primary", "backup"]
+
+[beacons.primary]
+url = "http://localhost:5052"
+
+[beacons.backup]
+url = "http://localhost:5053"
+`
+
+func writeAuthConfig(t *testing.T, dir, auth string) string {
+	t.Helper()
+	configPath := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(configPath, []byte(authTestBeacons+auth), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return configPath
+}
+
+func TestLoad_APIKeys(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TEST_CLIENT_KEY", "env-client-key")
+
+	keysFile := filepath.Join(dir, "keys.toml")
+	keysContent := `
+[[keys]]
+name = "indexer"
+key = "file-key"
+endpoints = ["^/eth/v1/beacon/headers"]
+rate_limit = 10
+nodes = ["backup"]
+`
+	if err := os.WriteFile(keysFile, []byte(keysContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	configPath := writeAuthConfig(t, dir, `
+[auth]
+enabled = true
+query_param = "api_key"
+keys_file = "`+keysFile+`"
+
+[[auth.keys]]
+name = "validator"
+key = "env:TEST_CLIENT_KEY"
+`)
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if cfg.Auth.Header != "X-API-Key" {
+		t.Errorf("Expected the default header, got %q", cfg.Auth.Header)
+	}
+	if len(cfg.Auth.Keys) != 2 {
+		t.Fatalf("Expected keys from the config and the keys file, got %d", len(cfg.Auth.Keys))
+	}
+	if got := cfg.Auth.Keys[0].Key.Value(); got != "env-client-key" {
+		t.Errorf("Expected the key read from the environment, got %q", got)
+	}
+	indexer := cfg.Auth.Keys[1]
+	if indexer.Name != "indexer" || indexer.Key.Value() != "file-key" || indexer.RateLimit != 10 ||
+		len(indexer.Endpoints) != 1 || len(indexer.Nodes) != 1 || indexer.Nodes[0] != "backup" {
+		t.Errorf("Expected the key from the keys file, got %+v", indexer)
+	}
+}
+
+func TestLoad_APIKeysErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		auth    string
+		wantErr string
+	}{
+		{"no keys", "[auth]\nenabled = true", "at least one key"},
+		{"no header or query parameter", "[auth]\nenabled = true\nheader = \"\"\n[[auth.keys]]\nname = \"a\"\nkey = \"k\"", "header or query_param"},
+		{"missing name", "[auth]\nenabled = true\n[[auth.keys]]\nkey = \"k\"", "name is required"},
+		{"missing key", "[auth]\nenabled = true\n[[auth.keys]]\nname = \"a\"", "key is required"},
+		{"duplicate name", "[auth]\nenabled = true\n[[auth.keys]]\nname = \"a\"\nkey = \"k1\"\n[[auth.keys]]\nname = \"a\"\nkey = \"k2\"", "duplicate auth key name"},
+		{"duplicate key", "[auth]\nenabled = true\n[[auth.keys]]\nname = \"a\"\nkey = \"k\"\n[[auth.keys]]\nname = \"b\"\nkey = \"k\"", "already used"},
+		{"invalid endpoint", "[auth]\nenabled = true\n[[auth.keys]]\nname = \"a\"\nkey = \"k\"\nendpoints = [\"[\"]", "invalid endpoint pattern"},
+		{"negative rate limit", "[auth]\nenabled = true\n[[auth.keys]]\nname = \"a\"\nkey = \"k\"\nrate_limit = -1", "rate_limit"},
+		{"unknown node", "[auth]\nenabled = true\n[[auth.keys]]\nname = \"a\"\nkey = \"k\"\nnodes = [\"missing\"]", "unknown beacon node"},
+		{"unset environment variable", "[auth]\nenabled = true\n[[auth.keys]]\nname = \"a\"\nkey = \"env:TEST_UNSET_CLIENT_KEY\"", "not set"},
+		{"missing keys file", "[auth]\nenabled = true\nkeys_file = \"/nonexistent/keys.toml\"", "keys_file"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load(writeAuthConfig(t, t.TempDir(), tc.auth))
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Expected an error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -2,7 +2,10 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/BurntSushi/toml"
@@ -10,27 +13,76 @@ import (
 
 // Config represents the main configuration structure
 type Config struct {
-	Server      ServerConfig      `toml:"server"`
-	Beacons     BeaconsConfig     `toml:"beacons"`
-	Failover    FailoverConfig    `toml:"failover"`
-	Metrics     MetricsConfig     `toml:"metrics"`
-	Logger      LoggerConfig      `toml:"logger"`
-	RateLimit   RateLimitConfig   `toml:"ratelimit"`
-	DNS         DNSConfig         `toml:"dns"`
-	Proxy       ProxyConfig       `toml:"proxy"`
-	WebSocket   WebSocketConfig   `toml:"websocket"`
-	HealthCheck HealthCheckConfig `toml:"health"`
+	Server        ServerConfig        `toml:"server"`
+	Beacons       BeaconsConfig       `toml:"beacons"`
+	Failover      FailoverConfig      `toml:"failover"`
+	Metrics       MetricsConfig       `toml:"metrics"`
+	Logger        LoggerConfig        `toml:"logger"`
+	RateLimit     RateLimitConfig     `toml:"ratelimit"`
+	DNS           DNSConfig           `toml:"dns"`
+	Proxy         ProxyConfig         `toml:"proxy"`
+	WebSocket     WebSocketConfig     `toml:"websocket"`
+	Events        EventsConfig        `toml:"events"`
+	Broadcast     BroadcastConfig     `toml:"broadcast"`
+	Hedging       HedgingConfig       `toml:"hedging"`
+	Quorum        QuorumConfig        `toml:"quorum"`
+	HeadTracking  HeadTrackingConfig  `toml:"head_tracking"`
+	ForkDetection ForkDetectionConfig `toml:"fork_detection"`
+	Breaker       BreakerConfig       `toml:"circuit_breaker"`
+	Routing       RoutingConfig       `toml:"routing"`
+	Routes        []RouteConfig       `toml:"routes"`
+	Capabilities  CapabilitiesConfig  `toml:"capabilities"`
+	HealthCheck   HealthCheckConfig   `toml:"healthcheck"`
+	Admin         AdminConfig         `toml:"admin"`
+	Reload        ReloadConfig        `toml:"reload"`
+	Auth          AuthConfig          `toml:"auth"`
 }
 
 // ServerConfig contains server-specific configuration
 type ServerConfig struct {
-	Port              int           `toml:"port"`
-	ReadTimeout       time.Duration `toml:"read_timeout"`
-	WriteTimeout      time.Duration `toml:"write_timeout"`
-	MaxRetries        int           `toml:"max_retries"`
-	RequestTimeout    time.Duration `toml:"request_timeout"`
-	IdleTimeout       time.Duration `toml:"idle_timeout"`
-	ReadHeaderTimeout time.Duration `toml:"read_header_timeout"`
+	Port                int           `toml:"port"`
+	ReadTimeout         time.Duration `toml:"read_timeout"`
+	WriteTimeout        time.Duration `toml:"write_timeout"`
+	MaxRetries          int           `toml:"max_retries"`
+	RequestTimeout      time.Duration `toml:"request_timeout"`
+	IdleTimeout         time.Duration `toml:"idle_timeout"`
+	ReadHeaderTimeout   time.Duration `toml:"read_header_timeout"`
+	MaxRequestBodySize  int64         `toml:"max_request_body_size"` // Largest request body buffered for replay across failover attempts
+	ShutdownDelay       time.Duration `toml:"shutdown_delay"`        // How long /readyz reports not ready before the listener closes on shutdown
+	ShutdownGracePeriod time.Duration `toml:"shutdown_grace_period"` // How long in-flight requests and streams get to finish on shutdown
+	TLS                 TLSConfig     `toml:"tls"`
+}
+
+// TLS versions accepted by min_version
+const (
+	TLSVersion12 = "1.2"
+	TLSVersion13 = "1.3"
+)
+
+// TLSConfig contains configuration for terminating TLS on the proxy listener
+type TLSConfig struct {
+	Enabled        bool          `toml:"enabled"`
+	CertFile       string        `toml:"cert_file"`       // PEM certificate chain
+	KeyFile        string        `toml:"key_file"`        // PEM private key
+	ClientCAFile   string        `toml:"client_ca_file"`  // PEM CA bundle; when set, clients must present a certificate it signed (mTLS)
+	MinVersion     string        `toml:"min_version"`     // Lowest TLS version accepted: 1.2 or 1.3
+	Port           int           `toml:"port"`            // Serve HTTPS on this port and plain HTTP on server port; 0 serves HTTPS on server port only
+	ReloadInterval time.Duration `toml:"reload_interval"` // How often the certificate files are checked for rotation
+}
+
+// AdminConfig contains configuration for the admin API, served on its own listener
+type AdminConfig struct {
+	Enabled bool   `toml:"enabled"`
+	Host    string `toml:"host"` // Interface to listen on, loopback by default
+	Port    int    `toml:"port"`
+	Token   Secret `toml:"token"` // Bearer token required by the endpoints that change state, or env:NAME or file:/path
+}
+
+// ReloadConfig contains configuration for reloading the config file while running. A reload
+// is always triggered by SIGHUP; watching the file also reloads it whenever it changes.
+type ReloadConfig struct {
+	Watch         bool          `toml:"watch"`          // Reload the config file when its modification time changes
+	WatchInterval time.Duration `toml:"watch_interval"` // How often the config file and API keys file are checked for changes
 }
 
 // RateLimitConfig contains rate limiting configuration
@@ -58,6 +110,8 @@ type ProxyConfig struct {
 	ResponseHeaderTimeout time.Duration `toml:"response_header_timeout"`
 	TLSHandshakeTimeout   time.Duration `toml:"tls_handshake_timeout"`
 	ExpectContinueTimeout time.Duration `toml:"expect_continue_timeout"`
+	StreamingThreshold    int           `toml:"streaming_threshold"` // Responses larger than this many bytes are streamed instead of buffered
+	StreamIdleTimeout     time.Duration `toml:"stream_idle_timeout"` // How long a streamed response may go without new bytes before it is cut off
 }
 
 // WebSocketConfig contains WebSocket configuration
@@ -67,11 +121,144 @@ type WebSocketConfig struct {
 	ErrorChannelBuffer int `toml:"error_channel_buffer"`
 }
 
+// EventsConfig contains event stream (`/eth/v1/events`) configuration
+type EventsConfig struct {
+	ClientBufferSize int           `toml:"client_buffer_size"` // Events queued per client before it is dropped as a slow consumer
+	ReconnectTimeout time.Duration `toml:"reconnect_timeout"`  // How long to keep retrying a lost upstream before disconnecting clients
+	ReconnectBackoff time.Duration `toml:"reconnect_backoff"`  // Delay between upstream reconnect attempts
+	DedupWindow      int           `toml:"dedup_window"`       // Number of recent head/block events remembered to drop duplicates after failover
+	IdleTimeout      time.Duration `toml:"idle_timeout"`       // How long an upstream may send nothing before the stream is migrated; 0 disables
+}
+
+// BroadcastConfig contains configuration for fanning submissions out to every healthy node
+type BroadcastConfig struct {
+	Enabled   bool          `toml:"enabled"`
+	Endpoints []string      `toml:"endpoints"` // Regex patterns of POST endpoints to broadcast
+	Timeout   time.Duration `toml:"timeout"`   // How long each node has to answer a broadcast submission
+}
+
+// HedgingConfig contains configuration for hedging latency-sensitive GET requests
+type HedgingConfig struct {
+	Enabled     bool          `toml:"enabled"`
+	Endpoints   []string      `toml:"endpoints"`    // Regex patterns of GET endpoints eligible for hedging
+	Delay       time.Duration `toml:"delay"`        // How long to wait for a node before sending the request to the next one
+	UseNodeP95  bool          `toml:"use_node_p95"` // Use the node's observed p95 latency as the delay once enough samples exist
+	BudgetRatio float64       `toml:"budget_ratio"` // Hedges earned per eligible request (0.1 = at most 10% extra requests)
+	BudgetBurst int           `toml:"budget_burst"` // Maximum hedges that can be saved up and sent back to back
+}
+
+// QuorumConfig contains configuration for reads that must agree across multiple nodes
+type QuorumConfig struct {
+	Enabled   bool          `toml:"enabled"`
+	Endpoints []string      `toml:"endpoints"` // Regex patterns of GET endpoints served by quorum
+	Nodes     int           `toml:"nodes"`     // Number of healthy nodes queried per request
+	Quorum    int           `toml:"quorum"`    // Number of nodes whose `data` must match
+	Timeout   time.Duration `toml:"timeout"`   // How long each node has to answer a quorum read
+}
+
+// HeadTrackingConfig contains configuration for tracking node heads and skipping lagging nodes
+type HeadTrackingConfig struct {
+	Enabled      bool          `toml:"enabled"`
+	PollInterval time.Duration `toml:"poll_interval"` // How often each node's head is fetched from /eth/v1/beacon/headers/head
+	MaxSlotLag   uint64        `toml:"max_slot_lag"`  // Nodes further behind the best known head are skipped
+}
+
+// ForkDetectionConfig contains configuration for comparing head and finalized roots across nodes
+// and quarantining nodes on a minority fork
+type ForkDetectionConfig struct {
+	Enabled       bool          `toml:"enabled"`
+	Interval      time.Duration `toml:"interval"`      // How often the nodes' roots are compared
+	Depth         uint64        `toml:"depth"`         // Slots behind the lowest head at which block roots are compared, clear of tip reorgs
+	Confirmations int           `toml:"confirmations"` // Consecutive comparisons a node must disagree in before it is quarantined
+}
+
+// Routing strategies
+const (
+	StrategyPriority           = "priority"             // Primary/backup failover by priority
+	StrategyWeightedRoundRobin = "weighted_round_robin" // Spread requests across nodes in proportion to their weight
+	StrategyLeastOutstanding   = "least_outstanding"    // Prefer the node with the fewest requests in flight
+	StrategyPeakEWMA           = "peak_ewma"            // Prefer the node with the lowest latency-EWMA times load
+)
+
+// BreakerConfig contains configuration for the per-node circuit breaker, which takes nodes
+// out of rotation when real traffic to them keeps failing or is slow
+type BreakerConfig struct {
+	Enabled            bool          `toml:"enabled"`
+	WindowSize         int           `toml:"window_size"`          // Number of recent requests the error and slow rates are computed over
+	MinRequests        int           `toml:"min_requests"`         // Requests needed in the window before the circuit can open
+	ErrorRateThreshold float64       `toml:"error_rate_threshold"` // Share of failed requests that opens the circuit
+	SlowCallDuration   time.Duration `toml:"slow_call_duration"`   // Requests slower than this count as slow (0 disables)
+	SlowRateThreshold  float64       `toml:"slow_rate_threshold"`  // Share of slow requests that opens the circuit
+	OpenDuration       time.Duration `toml:"open_duration"`        // How long an open circuit keeps the node out of rotation
+	HalfOpenProbes     int           `toml:"half_open_probes"`     // Consecutive successful probe requests needed to close the circuit
+}
+
+// RoutingConfig contains configuration for how requests are spread across healthy nodes
+type RoutingConfig struct {
+	Strategy  string        `toml:"strategy"`   // One of priority, weighted_round_robin, least_outstanding, peak_ewma
+	EWMADecay time.Duration `toml:"ewma_decay"` // Time window over which peak_ewma forgets old latencies
+}
+
+// RouteConfig maps request paths to a dedicated pool of nodes
+type RouteConfig struct {
+	Name     string   `toml:"name"`
+	Paths    []string `toml:"paths"`    // Regex patterns of request paths served by this pool
+	Nodes    []string `toml:"nodes"`    // Beacon names in the pool, in priority order
+	Strategy string   `toml:"strategy"` // Routing strategy within the pool (defaults to [routing] strategy)
+}
+
+// CapabilitiesConfig holds capability overrides keyed by beacon client type
+type CapabilitiesConfig map[string]CapabilityConfig
+
+// CapabilityConfig overrides the built-in capabilities of a beacon client type.
+// Fields that are left unset keep the built-in values.
+type CapabilityConfig struct {
+	UnsupportedEndpoints []string `toml:"unsupported_endpoints"` // Regex patterns of endpoints the client does not implement
+	ContentTypes         []string `toml:"content_types"`         // Media types the client can accept and serve
+}
+
+// beaconTypes lists the supported beacon client types
+var beaconTypes = map[string]bool{"lighthouse": true, "prysm": true, "nimbus": true, "teku": true, "erigon": true, "infura": true, "alchemy": true}
+
+// Health check probe types
+const (
+	ProbeSyncing       = "syncing"        // /eth/v1/node/syncing reports the node synced
+	ProbeNodeHealth    = "node_health"    // /eth/v1/node/health answers 200
+	ProbePeerCount     = "peer_count"     // The node has at least min_peers connected peers
+	ProbeHeadFreshness = "head_freshness" // The head is within max_slot_lag of the wall-clock slot
+	ProbeFinalityLag   = "finality_lag"   // The finalized epoch is within max_epoch_lag of the head epoch
+)
+
+// Default probe thresholds, used when a probe does not set them. The head_freshness default is
+// an epoch of mainnet slots, since missed slots leave every node behind the wall clock at once.
+const (
+	defaultProbeMinPeers    = 1
+	defaultProbeMaxSlotLag  = 32
+	defaultProbeMaxEpochLag = 4
+)
+
 // NodeConfig represents a beacon node configuration
 type NodeConfig struct {
-	Name string `toml:"name"`
-	URL  string `toml:"url"`
-	Type string `toml:"type"` // beacon client type: lighthouse, prysm, nimbus, teku, etc.
+	Name   string        `toml:"name"`
+	URL    string        `toml:"url"`
+	Type   string        `toml:"type"`   // beacon client type: lighthouse, prysm, nimbus, teku, etc.
+	Weight int           `toml:"weight"` // relative share of requests for weighted_round_robin (default 1)
+	Probes []ProbeConfig `toml:"probes"` // health check probes (defaults to syncing, plus node_health if check_node_health is set)
+
+	// Credentials sent with every request to the node, including health checks and websocket dials
+	Headers     map[string]Secret `toml:"headers"`      // extra request headers, such as provider API keys
+	BasicAuth   *BasicAuthConfig  `toml:"basic_auth"`   // HTTP basic authentication
+	BearerToken Secret            `toml:"bearer_token"` // sent as `Authorization: Bearer <token>`
+}
+
+// ProbeConfig configures one health check probe of a beacon node.
+// Only the threshold belonging to the probe's type is used.
+type ProbeConfig struct {
+	Type            string `toml:"type"`              // One of syncing, node_health, peer_count, head_freshness, finality_lag
+	MaxSyncDistance uint64 `toml:"max_sync_distance"` // syncing: highest sync_distance still healthy (defaults to [healthcheck] max_sync_distance)
+	MinPeers        int    `toml:"min_peers"`         // peer_count: fewest connected peers still healthy
+	MaxSlotLag      uint64 `toml:"max_slot_lag"`      // head_freshness: slots the head may trail the wall-clock slot
+	MaxEpochLag     uint64 `toml:"max_epoch_lag"`     // finality_lag: epochs the finalized checkpoint may trail the head
 }
 
 // BeaconsConfig contains all beacon node configurations
@@ -109,6 +296,12 @@ type HealthCheckConfig struct {
 	Interval                    time.Duration `toml:"interval"`                       // How often to run health checks
 	Timeout                     time.Duration `toml:"timeout"`                        // Timeout for individual health check requests
 	SuccessfulChecksForFailback int           `toml:"successful_checks_for_failback"` // Number of consecutive successful checks before failing back to original primary
+	PrimaryFailuresForDemotion  int           `toml:"primary_failures_for_demotion"`  // Number of consecutive failed checks before the primary is demoted
+	MaxSyncDistance             uint64        `toml:"max_sync_distance"`              // Highest sync_distance a node may report and still be healthy
+	CheckNodeHealth             bool          `toml:"check_node_health"`              // Also require /eth/v1/node/health to answer 200 (206 means syncing)
+	SecondsPerSlot              int           `toml:"seconds_per_slot"`               // Slot duration of the chain, used by head_freshness probes
+	SlotsPerEpoch               int           `toml:"slots_per_epoch"`                // Slots per epoch of the chain, used by finality_lag probes
+	MinHealthyNodes             int           `toml:"min_healthy_nodes"`              // Nodes that must be able to take traffic for /readyz to report ready
 }
 
 // Load loads configuration from a TOML file with sensible defaults
@@ -132,6 +325,18 @@ func Load(configPath string) (*Config, error) {
 		if err := config.parseBeaconConfigs(rawConfig); err != nil {
 			return nil, fmt.Errorf("failed to parse beacon configurations: %v", err)
 		}
+
+		// Load client API keys
+		if err := config.loadAPIKeys(); err != nil {
+			return nil, fmt.Errorf("failed to load auth keys: %v", err)
+		}
+
+		// Resolve the admin token
+		token, err := resolveSecret(config.Admin.Token.Value())
+		if err != nil {
+			return nil, fmt.Errorf("admin token: %v", err)
+		}
+		config.Admin.Token = token
 	} else {
 		return nil, fmt.Errorf("config file not found: %s", configPath)
 	}
@@ -182,16 +387,80 @@ func (c *Config) parseBeaconConfigs(rawConfig map[string]interface{}) error {
 			beaconType = t
 		}
 
-		c.Beacons.parsedNodes = append(c.Beacons.parsedNodes, NodeConfig{
-			Name: beaconName,
-			URL:  url,
-			Type: beaconType,
-		})
+		// Extract weight (optional)
+		weight := 1
+		if raw, ok := beaconConfig["weight"]; ok {
+			w, ok := raw.(int64)
+			if !ok {
+				return fmt.Errorf("beacon %s: weight must be an integer", beaconName)
+			}
+			weight = int(w)
+		}
+
+		// Extract probes (optional)
+		probes, err := c.parseProbeConfigs(beaconConfig["probes"])
+		if err != nil {
+			return fmt.Errorf("beacon %s: %v", beaconName, err)
+		}
+
+		node := NodeConfig{
+			Name:   beaconName,
+			URL:    url,
+			Type:   beaconType,
+			Weight: weight,
+			Probes: probes,
+		}
+
+		// Extract upstream credentials (optional)
+		if err := parseNodeAuth(beaconConfig, &node); err != nil {
+			return fmt.Errorf("beacon %s: %v", beaconName, err)
+		}
+
+		c.Beacons.parsedNodes = append(c.Beacons.parsedNodes, node)
 	}
 
 	return nil
 }
 
+// parseProbeConfigs parses a beacon's `[[beacons.<name>.probes]]` tables, filling in default
+// thresholds for any that are not set
+func (c *Config) parseProbeConfigs(raw interface{}) ([]ProbeConfig, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	tables, ok := raw.([]map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("probes must be an array of tables")
+	}
+
+	probes := make([]ProbeConfig, 0, len(tables))
+	for _, table := range tables {
+		probe := ProbeConfig{
+			MaxSyncDistance: c.HealthCheck.MaxSyncDistance,
+			MinPeers:        defaultProbeMinPeers,
+			MaxSlotLag:      defaultProbeMaxSlotLag,
+			MaxEpochLag:     defaultProbeMaxEpochLag,
+		}
+		if t, ok := table["type"].(string); ok {
+			probe.Type = t
+		}
+		if v, ok := table["max_sync_distance"].(int64); ok {
+			probe.MaxSyncDistance = uint64(v)
+		}
+		if v, ok := table["min_peers"].(int64); ok {
+			probe.MinPeers = int(v)
+		}
+		if v, ok := table["max_slot_lag"].(int64); ok {
+			probe.MaxSlotLag = uint64(v)
+		}
+		if v, ok := table["max_epoch_lag"].(int64); ok {
+			probe.MaxEpochLag = uint64(v)
+		}
+		probes = append(probes, probe)
+	}
+	return probes, nil
+}
+
 // LoadOrDefault loads configuration from file, or returns defaults if file doesn't exist
 func LoadOrDefault(configPath string) *Config {
 	config, err := Load(configPath)
@@ -205,13 +474,35 @@ func LoadOrDefault(configPath string) *Config {
 func getDefaultConfig() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Port:              8080,
-			ReadTimeout:       30 * time.Second,
-			WriteTimeout:      30 * time.Second,
-			MaxRetries:        3,
-			RequestTimeout:    30 * time.Millisecond,
-			IdleTimeout:       90 * time.Second,
-			ReadHeaderTimeout: 10 * time.Second,
+			Port:                8080,
+			ReadTimeout:         30 * time.Second,
+			WriteTimeout:        30 * time.Second,
+			MaxRetries:          3,
+			RequestTimeout:      30 * time.Millisecond,
+			IdleTimeout:         90 * time.Second,
+			ReadHeaderTimeout:   10 * time.Second,
+			MaxRequestBodySize:  16 << 20,
+			ShutdownDelay:       5 * time.Second,
+			ShutdownGracePeriod: 30 * time.Second,
+			TLS: TLSConfig{
+				Enabled:        false,
+				MinVersion:     TLSVersion12,
+				Port:           0,
+				ReloadInterval: time.Minute,
+			},
+		},
+		Admin: AdminConfig{
+			Enabled: false,
+			Host:    "127.0.0.1",
+			Port:    8081,
+		},
+		Reload: ReloadConfig{
+			Watch:         false,
+			WatchInterval: 5 * time.Second,
+		},
+		Auth: AuthConfig{
+			Enabled: false,
+			Header:  "X-API-Key",
 		},
 		Failover: FailoverConfig{
 			ErrorThreshold: 5,
@@ -246,16 +537,93 @@ func getDefaultConfig() *Config {
 			ResponseHeaderTimeout: 10 * time.Second,
 			TLSHandshakeTimeout:   10 * time.Second,
 			ExpectContinueTimeout: 1 * time.Second,
+			StreamingThreshold:    1 << 20,
+			StreamIdleTimeout:     30 * time.Second,
 		},
 		WebSocket: WebSocketConfig{
 			ReadBufferSize:     4096,
 			WriteBufferSize:    4096,
 			ErrorChannelBuffer: 100,
 		},
+		Events: EventsConfig{
+			ClientBufferSize: 256,
+			ReconnectTimeout: 30 * time.Second,
+			ReconnectBackoff: 500 * time.Millisecond,
+			DedupWindow:      64,
+			IdleTimeout:      time.Minute,
+		},
+		Broadcast: BroadcastConfig{
+			Enabled: false,
+			Endpoints: []string{
+				`^/eth/v1/beacon/pool/[^/]+$`,
+				`^/eth/v2/beacon/pool/attestations$`,
+				`^/eth/v[12]/beacon/blocks$`,
+				`^/eth/v[12]/beacon/blinded_blocks$`,
+				`^/eth/v1/validator/contribution_and_proofs$`,
+				`^/eth/v[12]/validator/aggregate_and_proofs$`,
+			},
+			Timeout: 4 * time.Second,
+		},
+		Hedging: HedgingConfig{
+			Enabled: false,
+			Endpoints: []string{
+				`^/eth/v1/validator/attestation_data$`,
+				`^/eth/v[12]/validator/aggregate_attestation$`,
+				`^/eth/v1/validator/sync_committee_contribution$`,
+				`^/eth/v1/validator/duties/proposer/[^/]+$`,
+			},
+			Delay:       200 * time.Millisecond,
+			UseNodeP95:  false,
+			BudgetRatio: 0.1,
+			BudgetBurst: 10,
+		},
+		Quorum: QuorumConfig{
+			Enabled: false,
+			Endpoints: []string{
+				`^/eth/v1/beacon/states/[^/]+/finality_checkpoints$`,
+				`^/eth/v1/beacon/genesis$`,
+				`^/eth/v1/config/spec$`,
+				`^/eth/v1/beacon/headers/finalized$`,
+			},
+			Nodes:   3,
+			Quorum:  2,
+			Timeout: 4 * time.Second,
+		},
+		HeadTracking: HeadTrackingConfig{
+			Enabled:      false,
+			PollInterval: 2 * time.Second,
+			MaxSlotLag:   2,
+		},
+		ForkDetection: ForkDetectionConfig{
+			Enabled:       false,
+			Interval:      12 * time.Second,
+			Depth:         4,
+			Confirmations: 3,
+		},
+		Breaker: BreakerConfig{
+			Enabled:            false,
+			WindowSize:         50,
+			MinRequests:        20,
+			ErrorRateThreshold: 0.5,
+			SlowCallDuration:   5 * time.Second,
+			SlowRateThreshold:  0.8,
+			OpenDuration:       30 * time.Second,
+			HalfOpenProbes:     3,
+		},
+		Routing: RoutingConfig{
+			Strategy:  StrategyPriority,
+			EWMADecay: 10 * time.Second,
+		},
 		HealthCheck: HealthCheckConfig{
 			Interval:                    30 * time.Second,
 			Timeout:                     5 * time.Second,
 			SuccessfulChecksForFailback: 3,
+			PrimaryFailuresForDemotion:  2,
+			MaxSyncDistance:             0,
+			CheckNodeHealth:             false,
+			SecondsPerSlot:              12,
+			SlotsPerEpoch:               32,
+			MinHealthyNodes:             1,
 		},
 	}
 }
@@ -284,6 +652,54 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid server port: %d", c.Server.Port)
 	}
 
+	if c.Admin.Enabled {
+		if c.Admin.Port < 1 || c.Admin.Port > 65535 {
+			return fmt.Errorf("invalid admin port: %d", c.Admin.Port)
+		}
+		if c.Admin.Port == c.Server.Port {
+			return fmt.Errorf("admin port must differ from server port (%d)", c.Server.Port)
+		}
+		// Cordon, drain and promote can take every node out of rotation, so they are never
+		// served without a token, even on loopback
+		if c.Admin.Token == "" {
+			return fmt.Errorf("admin token is required when the admin API is enabled")
+		}
+	}
+
+	if c.Server.ShutdownDelay < 0 {
+		return fmt.Errorf("server shutdown_delay must not be negative")
+	}
+	if c.Server.ShutdownGracePeriod <= 0 {
+		return fmt.Errorf("server shutdown_grace_period must be positive")
+	}
+
+	if c.Server.TLS.Enabled {
+		if c.Server.TLS.CertFile == "" || c.Server.TLS.KeyFile == "" {
+			return fmt.Errorf("server tls requires cert_file and key_file")
+		}
+		if c.Server.TLS.MinVersion != TLSVersion12 && c.Server.TLS.MinVersion != TLSVersion13 {
+			return fmt.Errorf("invalid server tls min_version: %q (must be %s or %s)", c.Server.TLS.MinVersion, TLSVersion12, TLSVersion13)
+		}
+		if c.Server.TLS.ReloadInterval <= 0 {
+			return fmt.Errorf("server tls reload_interval must be positive")
+		}
+		if c.Server.TLS.Port != 0 {
+			if c.Server.TLS.Port < 1 || c.Server.TLS.Port > 65535 {
+				return fmt.Errorf("invalid server tls port: %d", c.Server.TLS.Port)
+			}
+			if c.Server.TLS.Port == c.Server.Port {
+				return fmt.Errorf("server tls port must differ from server port (%d)", c.Server.Port)
+			}
+			if c.Admin.Enabled && c.Server.TLS.Port == c.Admin.Port {
+				return fmt.Errorf("server tls port must differ from admin port (%d)", c.Admin.Port)
+			}
+		}
+	}
+
+	if (c.Reload.Watch || c.Auth.KeysFile != "") && c.Reload.WatchInterval <= 0 {
+		return fmt.Errorf("reload watch_interval must be positive")
+	}
+
 	if c.Server.MaxRetries < 1 {
 		return fmt.Errorf("max_retries must be at least 1")
 	}
@@ -292,6 +708,18 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("request_timeout must be positive")
 	}
 
+	if c.Server.MaxRequestBodySize < 1 {
+		return fmt.Errorf("max_request_body_size must be at least 1")
+	}
+
+	if c.Proxy.StreamingThreshold < 0 {
+		return fmt.Errorf("proxy streaming_threshold cannot be negative")
+	}
+
+	if c.Proxy.StreamIdleTimeout <= 0 {
+		return fmt.Errorf("proxy stream_idle_timeout must be positive")
+	}
+
 	if c.Failover.ErrorThreshold < 1 {
 		return fmt.Errorf("failover error_threshold must be at least 1")
 	}
@@ -307,11 +735,17 @@ func (c *Config) Validate() error {
 
 		// Validate beacon type if specified
 		if node.Type != "" {
-			validTypes := map[string]bool{"lighthouse": true, "prysm": true, "nimbus": true, "teku": true, "erigon": true, "infura": true, "alchemy": true}
-			if !validTypes[node.Type] {
+			if !beaconTypes[node.Type] {
 				return fmt.Errorf("node %d (%s): invalid beacon type '%s' (valid types: lighthouse, prysm, nimbus, teku, erigon, infura, alchemy)", i, node.Name, node.Type)
 			}
 		}
+
+		if err := validateProbes(node.Probes); err != nil {
+			return fmt.Errorf("node %d (%s): %v", i, node.Name, err)
+		}
+		if err := validateNodeAuth(node); err != nil {
+			return fmt.Errorf("node %d (%s): %v", i, node.Name, err)
+		}
 	}
 
 	// Validate logger configuration
@@ -335,6 +769,150 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Validate event stream configuration
+	if c.Events.ClientBufferSize < 1 {
+		return fmt.Errorf("events client_buffer_size must be at least 1")
+	}
+	if c.Events.ReconnectTimeout < 0 {
+		return fmt.Errorf("events reconnect_timeout cannot be negative")
+	}
+	if c.Events.ReconnectBackoff <= 0 {
+		return fmt.Errorf("events reconnect_backoff must be positive")
+	}
+	if c.Events.DedupWindow < 0 {
+		return fmt.Errorf("events dedup_window cannot be negative")
+	}
+	if c.Events.IdleTimeout < 0 {
+		return fmt.Errorf("events idle_timeout cannot be negative")
+	}
+
+	// Validate broadcast configuration
+	if c.Broadcast.Enabled {
+		if c.Broadcast.Timeout <= 0 {
+			return fmt.Errorf("broadcast timeout must be positive")
+		}
+		for _, pattern := range c.Broadcast.Endpoints {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid broadcast endpoint pattern '%s': %v", pattern, err)
+			}
+		}
+	}
+
+	// Validate hedging configuration
+	if c.Hedging.Enabled {
+		if c.Hedging.Delay <= 0 {
+			return fmt.Errorf("hedging delay must be positive")
+		}
+		if c.Hedging.BudgetRatio <= 0 || c.Hedging.BudgetRatio > 1 {
+			return fmt.Errorf("hedging budget_ratio must be greater than 0 and at most 1")
+		}
+		if c.Hedging.BudgetBurst < 1 {
+			return fmt.Errorf("hedging budget_burst must be at least 1")
+		}
+		for _, pattern := range c.Hedging.Endpoints {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid hedging endpoint pattern '%s': %v", pattern, err)
+			}
+		}
+	}
+
+	// Validate quorum configuration
+	if c.Quorum.Enabled {
+		if c.Quorum.Quorum < 1 {
+			return fmt.Errorf("quorum quorum must be at least 1")
+		}
+		if c.Quorum.Nodes < c.Quorum.Quorum {
+			return fmt.Errorf("quorum nodes (%d) must be at least quorum (%d)", c.Quorum.Nodes, c.Quorum.Quorum)
+		}
+		// Anything short of a majority lets two disagreeing groups of nodes both reach quorum
+		if c.Quorum.Quorum <= c.Quorum.Nodes/2 {
+			return fmt.Errorf("quorum (%d) must be a majority of quorum nodes (%d)", c.Quorum.Quorum, c.Quorum.Nodes)
+		}
+		if c.Quorum.Timeout <= 0 {
+			return fmt.Errorf("quorum timeout must be positive")
+		}
+		for _, pattern := range c.Quorum.Endpoints {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid quorum endpoint pattern '%s': %v", pattern, err)
+			}
+		}
+	}
+
+	// Validate head tracking configuration
+	if c.HeadTracking.Enabled && c.HeadTracking.PollInterval <= 0 {
+		return fmt.Errorf("head_tracking poll_interval must be positive")
+	}
+
+	// Validate fork detection configuration
+	if c.ForkDetection.Enabled && c.ForkDetection.Interval <= 0 {
+		return fmt.Errorf("fork_detection interval must be positive")
+	}
+	if c.ForkDetection.Enabled && c.ForkDetection.Confirmations < 1 {
+		return fmt.Errorf("fork_detection confirmations must be at least 1")
+	}
+
+	// Validate circuit breaker configuration
+	if c.Breaker.Enabled {
+		if c.Breaker.WindowSize < 1 {
+			return fmt.Errorf("circuit_breaker window_size must be at least 1")
+		}
+		if c.Breaker.MinRequests < 1 || c.Breaker.MinRequests > c.Breaker.WindowSize {
+			return fmt.Errorf("circuit_breaker min_requests must be between 1 and window_size (%d)", c.Breaker.WindowSize)
+		}
+		if c.Breaker.ErrorRateThreshold <= 0 || c.Breaker.ErrorRateThreshold > 1 {
+			return fmt.Errorf("circuit_breaker error_rate_threshold must be greater than 0 and at most 1")
+		}
+		if c.Breaker.SlowCallDuration < 0 {
+			return fmt.Errorf("circuit_breaker slow_call_duration cannot be negative")
+		}
+		if c.Breaker.SlowRateThreshold <= 0 || c.Breaker.SlowRateThreshold > 1 {
+			return fmt.Errorf("circuit_breaker slow_rate_threshold must be greater than 0 and at most 1")
+		}
+		if c.Breaker.OpenDuration <= 0 {
+			return fmt.Errorf("circuit_breaker open_duration must be positive")
+		}
+		if c.Breaker.HalfOpenProbes < 1 {
+			return fmt.Errorf("circuit_breaker half_open_probes must be at least 1")
+		}
+	}
+
+	// Validate routing configuration
+	if err := validateStrategy(c.Routing.Strategy); err != nil {
+		return err
+	}
+	if c.Routing.Strategy == StrategyPeakEWMA && c.Routing.EWMADecay <= 0 {
+		return fmt.Errorf("routing ewma_decay must be positive")
+	}
+	for _, node := range allNodes {
+		if node.Weight < 1 {
+			return fmt.Errorf("beacon %s: weight must be at least 1", node.Name)
+		}
+	}
+
+	// Validate routes
+	if err := c.validateAuth(); err != nil {
+		return err
+	}
+
+	if err := c.validateRoutes(); err != nil {
+		return err
+	}
+
+	// Validate capability overrides
+	for beaconType, capability := range c.Capabilities {
+		if !beaconTypes[beaconType] {
+			return fmt.Errorf("capabilities: invalid beacon type '%s'", beaconType)
+		}
+		for _, pattern := range capability.UnsupportedEndpoints {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("capabilities %s: invalid endpoint pattern '%s': %v", beaconType, pattern, err)
+			}
+		}
+		if capability.ContentTypes != nil && len(capability.ContentTypes) == 0 {
+			return fmt.Errorf("capabilities %s: content_types cannot be empty", beaconType)
+		}
+	}
+
 	// Validate health check configuration
 	if c.HealthCheck.Interval <= 0 {
 		return fmt.Errorf("health check interval must be positive")
@@ -348,10 +926,103 @@ func (c *Config) Validate() error {
 	if c.HealthCheck.SuccessfulChecksForFailback < 1 {
 		return fmt.Errorf("health check successful_checks_for_failback must be at least 1")
 	}
+	if c.HealthCheck.PrimaryFailuresForDemotion < 1 {
+		return fmt.Errorf("health check primary_failures_for_demotion must be at least 1")
+	}
+	if c.HealthCheck.SecondsPerSlot < 1 {
+		return fmt.Errorf("health check seconds_per_slot must be at least 1")
+	}
+	if c.HealthCheck.SlotsPerEpoch < 1 {
+		return fmt.Errorf("health check slots_per_epoch must be at least 1")
+	}
+	if c.HealthCheck.MinHealthyNodes < 1 || c.HealthCheck.MinHealthyNodes > len(allNodes) {
+		return fmt.Errorf("health check min_healthy_nodes must be between 1 and the number of beacon nodes (%d)", len(allNodes))
+	}
 
 	return nil
 }
 
+// validateRoutes checks that every route has paths and only refers to configured beacons
+func (c *Config) validateRoutes() error {
+	beacons := make(map[string]bool, len(c.Beacons.Nodes))
+	for _, name := range c.Beacons.Nodes {
+		beacons[name] = true
+	}
+
+	names := make(map[string]bool, len(c.Routes))
+	for _, route := range c.Routes {
+		if route.Name == "" {
+			return fmt.Errorf("route name is required")
+		}
+		if names[route.Name] {
+			return fmt.Errorf("duplicate route name: %s", route.Name)
+		}
+		names[route.Name] = true
+
+		if len(route.Paths) == 0 {
+			return fmt.Errorf("route %s: at least one path is required", route.Name)
+		}
+		for _, pattern := range route.Paths {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("route %s: invalid path pattern '%s': %v", route.Name, pattern, err)
+			}
+		}
+
+		if len(route.Nodes) == 0 {
+			return fmt.Errorf("route %s: at least one node is required", route.Name)
+		}
+		for _, node := range route.Nodes {
+			if !beacons[node] {
+				return fmt.Errorf("route %s: unknown beacon node: %s", route.Name, node)
+			}
+		}
+
+		if route.Strategy != "" {
+			if err := validateStrategy(route.Strategy); err != nil {
+				return fmt.Errorf("route %s: %v", route.Name, err)
+			}
+			if route.Strategy == StrategyPeakEWMA && c.Routing.EWMADecay <= 0 {
+				return fmt.Errorf("route %s: routing ewma_decay must be positive", route.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateProbes checks that every probe has a known type and a usable threshold, and that no
+// probe type is listed twice
+func validateProbes(probes []ProbeConfig) error {
+	seen := make(map[string]bool, len(probes))
+	for _, probe := range probes {
+		switch probe.Type {
+		case ProbeSyncing, ProbeNodeHealth, ProbeHeadFreshness, ProbeFinalityLag:
+		case ProbePeerCount:
+			if probe.MinPeers < 1 {
+				return fmt.Errorf("peer_count probe min_peers must be at least 1")
+			}
+		default:
+			return fmt.Errorf("invalid probe type '%s' (must be one of: %s, %s, %s, %s, %s)", probe.Type,
+				ProbeSyncing, ProbeNodeHealth, ProbePeerCount, ProbeHeadFreshness, ProbeFinalityLag)
+		}
+		if seen[probe.Type] {
+			return fmt.Errorf("duplicate probe type: %s", probe.Type)
+		}
+		seen[probe.Type] = true
+	}
+	return nil
+}
+
+// validateStrategy checks that a routing strategy name is known
+func validateStrategy(strategy string) error {
+	switch strategy {
+	case StrategyPriority, StrategyWeightedRoundRobin, StrategyLeastOutstanding, StrategyPeakEWMA:
+		return nil
+	default:
+		return fmt.Errorf("invalid routing strategy '%s' (must be one of: %s, %s, %s, %s)", strategy,
+			StrategyPriority, StrategyWeightedRoundRobin, StrategyLeastOutstanding, StrategyPeakEWMA)
+	}
+}
+
 // GetAllNodes returns all configured beacon nodes
 // The first beacon node is always treated as primary, followed by other beacon nodes in order
 func (c *Config) GetAllNodes() []NodeConfig {
@@ -362,3 +1033,13 @@ func (c *Config) GetAllNodes() []NodeConfig {
 func (c *Config) GetListenAddr() string {
 	return fmt.Sprintf(":%d", c.Server.Port)
 }
+
+// GetTLSListenAddr returns the formatted listen address of the separate HTTPS listener
+func (c *Config) GetTLSListenAddr() string {
+	return fmt.Sprintf(":%d", c.Server.TLS.Port)
+}
+
+// GetAdminListenAddr returns the formatted listen address of the admin API
+func (c *Config) GetAdminListenAddr() string {
+	return net.JoinHostPort(c.Admin.Host, strconv.Itoa(c.Admin.Port))
+}
@@ -1,7 +1,10 @@
 package config
 
 import (
+	"context"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -67,6 +70,14 @@ type = "lighthouse"
 [beacons.test-node-2]
 url = "http://localhost:5053"
 type = "prysm"
+weight = 3
+
+[[beacons.test-node-2.probes]]
+type = "syncing"
+
+[[beacons.test-node-2.probes]]
+type = "peer_count"
+min_peers = 8
 `
 
 	tmpFile, err := os.CreateTemp("", "test-config-*.toml")
@@ -228,6 +239,38 @@ type = "prysm"
 	if allNodes[1].Type != "prysm" {
 		t.Errorf("Expected second node type=prysm, got %s", allNodes[1].Type)
 	}
+
+	if allNodes[0].Weight != 1 {
+		t.Errorf("Expected first node default weight=1, got %d", allNodes[0].Weight)
+	}
+
+	if allNodes[1].Weight != 3 {
+		t.Errorf("Expected second node weight=3, got %d", allNodes[1].Weight)
+	}
+
+	if len(allNodes[0].Probes) != 0 {
+		t.Errorf("Expected first node to use default probes, got %v", allNodes[0].Probes)
+	}
+
+	if len(allNodes[1].Probes) != 2 {
+		t.Fatalf("Expected second node to have 2 probes, got %d", len(allNodes[1].Probes))
+	}
+
+	if allNodes[1].Probes[0].Type != ProbeSyncing || allNodes[1].Probes[1].Type != ProbePeerCount {
+		t.Errorf("Expected probes syncing and peer_count, got %s and %s", allNodes[1].Probes[0].Type, allNodes[1].Probes[1].Type)
+	}
+
+	if allNodes[1].Probes[1].MinPeers != 8 {
+		t.Errorf("Expected peer_count min_peers=8, got %d", allNodes[1].Probes[1].MinPeers)
+	}
+
+	if allNodes[1].Probes[1].MaxEpochLag != defaultProbeMaxEpochLag {
+		t.Errorf("Expected unset max_epoch_lag to default to %d, got %d", defaultProbeMaxEpochLag, allNodes[1].Probes[1].MaxEpochLag)
+	}
+
+	if allNodes[1].Probes[1].MaxSlotLag != 32 {
+		t.Errorf("Expected unset max_slot_lag to default to an epoch of slots, got %d", allNodes[1].Probes[1].MaxSlotLag)
+	}
 }
 
 func TestConfigLoadOrDefault(t *testing.T) {
@@ -264,7 +307,7 @@ func TestConfigValidation(t *testing.T) {
 	// Start with default config which has all required fields set
 	cfg := LoadOrDefault("nonexistent-file-to-get-defaults.toml")
 	cfg.Beacons.Nodes = []string{"test"}
-	cfg.Beacons.SetParsedNodes([]NodeConfig{{Name: "test", URL: "http://localhost:5052"}})
+	cfg.Beacons.SetParsedNodes([]NodeConfig{{Name: "test", URL: "http://localhost:5052", Weight: 1}})
 
 	// Test invalid port
 	cfg.Server.Port = -1
@@ -286,16 +329,212 @@ func TestConfigValidation(t *testing.T) {
 		t.Error("Expected validation error for invalid error_threshold")
 	}
 
+	// Test non-positive stream idle timeout
+	cfg.Failover.ErrorThreshold = 5
+	cfg.Proxy.StreamIdleTimeout = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for non-positive stream_idle_timeout")
+	}
+
+	// Test invalid routing strategy
+	cfg.Proxy.StreamIdleTimeout = 30 * time.Second
+	cfg.Routing.Strategy = "random"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for unknown routing strategy")
+	}
+
+	// Test routes referencing an unknown node
+	cfg.Routing.Strategy = StrategyPriority
+	cfg.Routes = []RouteConfig{{Name: "archive", Paths: []string{"^/eth/v1/debug/.*$"}, Nodes: []string{"missing"}}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for route with unknown node")
+	}
+
+	// Test duplicate route names
+	cfg.Routes = []RouteConfig{
+		{Name: "archive", Paths: []string{"^/eth/v1/debug/.*$"}, Nodes: cfg.Beacons.Nodes[:1]},
+		{Name: "archive", Paths: []string{"^/eth/v1/beacon/rewards/.*$"}, Nodes: cfg.Beacons.Nodes[:1]},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for duplicate route name")
+	}
+
+	// Test capability overrides for an unknown client type
+	cfg.Routes = nil
+	cfg.Capabilities = CapabilitiesConfig{"lodestar": {ContentTypes: []string{"application/json"}}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for capabilities of unknown beacon type")
+	}
+
+	// Test circuit breaker min_requests larger than its window
+	cfg.Capabilities = nil
+	cfg.Breaker.Enabled = true
+	cfg.Breaker.MinRequests = cfg.Breaker.WindowSize + 1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for circuit_breaker min_requests above window_size")
+	}
+
+	// Test unknown and duplicate probe types
+	cfg.Breaker.MinRequests = 20
+	cfg.Beacons.SetParsedNodes([]NodeConfig{{Name: "test", URL: "http://localhost:5052", Weight: 1, Probes: []ProbeConfig{{Type: "ping"}}}})
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for unknown probe type")
+	}
+	cfg.Beacons.SetParsedNodes([]NodeConfig{{Name: "test", URL: "http://localhost:5052", Weight: 1, Probes: []ProbeConfig{{Type: ProbeSyncing}, {Type: ProbeSyncing}}}})
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for duplicate probe type")
+	}
+
+	// Test fork detection that quarantines without any confirmation
+	cfg.Beacons.SetParsedNodes([]NodeConfig{{Name: "test", URL: "http://localhost:5052", Weight: 1}})
+	cfg.ForkDetection.Enabled = true
+	cfg.ForkDetection.Confirmations = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for fork_detection confirmations below 1")
+	}
+	cfg.ForkDetection.Confirmations = 3
+
+	// Test a quorum that is not a majority of the queried nodes
+	cfg.Quorum.Enabled = true
+	cfg.Quorum.Nodes, cfg.Quorum.Quorum = 4, 2
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for a quorum of half the quorum nodes")
+	}
+	cfg.Quorum.Nodes, cfg.Quorum.Quorum = 3, 2
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected a majority quorum to be valid: %v", err)
+	}
+	cfg.Quorum.Enabled = false
+
+	// Test a non-positive shutdown grace period
+	cfg.Beacons.SetParsedNodes([]NodeConfig{{Name: "test", URL: "http://localhost:5052", Weight: 1}})
+	cfg.Server.ShutdownGracePeriod = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for non-positive shutdown_grace_period")
+	}
+
+	// Test min_healthy_nodes above the number of nodes
+	cfg.Server.ShutdownGracePeriod = 30 * time.Second
+	cfg.HealthCheck.MinHealthyNodes = 2
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for min_healthy_nodes above the number of nodes")
+	}
+
+	// Test TLS without a certificate, with an unknown version and on the server port
+	cfg.HealthCheck.MinHealthyNodes = 1
+	cfg.Server.TLS.Enabled = true
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for tls without cert_file and key_file")
+	}
+	cfg.Server.TLS.CertFile, cfg.Server.TLS.KeyFile = "tls.crt", "tls.key"
+	cfg.Server.TLS.MinVersion = "1.1"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for unsupported tls min_version")
+	}
+	cfg.Server.TLS.MinVersion = TLSVersion13
+	cfg.Server.TLS.Port = cfg.Server.Port
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for tls port equal to server port")
+	}
+	cfg.Server.TLS.Port = 8443
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected separate HTTP and HTTPS ports to be valid: %v", err)
+	}
+
+	// Test an admin API without a token, on loopback and on every interface
+	cfg.Admin.Enabled = true
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for an admin API on loopback without a token")
+	}
+	cfg.Admin.Host = ""
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for an admin API on every interface without a token")
+	}
+	cfg.Admin.Token = "admin-token"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected an admin API with a token to be valid: %v", err)
+	}
+
 	// Test valid configuration with defaults
 	// Reset to valid values (using defaults that were already loaded)
 	cfg = LoadOrDefault("nonexistent-file-to-get-defaults.toml")
 	cfg.Beacons.Nodes = []string{"test"}
-	cfg.Beacons.SetParsedNodes([]NodeConfig{{Name: "test", URL: "http://localhost:5052"}})
+	cfg.Beacons.SetParsedNodes([]NodeConfig{{Name: "test", URL: "http://localhost:5052", Weight: 1}})
 	if err := cfg.Validate(); err != nil {
 		t.Errorf("Valid configuration with defaults should not produce error: %v", err)
 	}
 }
 
+func TestLoad_WeightErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		weight  string
+		wantErr string
+	}{
+		{"zero", "0", "weight must be at least 1"},
+		{"negative", "-2", "weight must be at least 1"},
+		{"float", "1.5", "weight must be an integer"},
+		{"string", `"2"`, "weight must be an integer"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.toml")
+			content := "[beacons]\nnodes = [\"test\"]\n\n[beacons.test]\nurl = \"http://localhost:5052\"\nweight = " + tc.weight + "\n"
+			if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(configPath)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Expected an error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestLoad_ExampleHealthCheck tests that the [healthcheck] keys documented in config.toml.example
+// are read, by loading the example with non-default values
+func TestLoad_ExampleHealthCheck(t *testing.T) {
+	example, err := os.ReadFile("../../config.toml.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name    string
+		line    string
+		changed string
+		check   func(HealthCheckConfig) bool
+	}{
+		{"primary_failures_for_demotion", "primary_failures_for_demotion = 2 ", "primary_failures_for_demotion = 5 ", func(h HealthCheckConfig) bool { return h.PrimaryFailuresForDemotion == 5 }},
+		{"max_sync_distance", "max_sync_distance = 0 ", "max_sync_distance = 4 ", func(h HealthCheckConfig) bool { return h.MaxSyncDistance == 4 }},
+		{"check_node_health", "check_node_health = false ", "check_node_health = true ", func(h HealthCheckConfig) bool { return h.CheckNodeHealth }},
+		{"seconds_per_slot", "seconds_per_slot = 12 ", "seconds_per_slot = 6 ", func(h HealthCheckConfig) bool { return h.SecondsPerSlot == 6 }},
+		{"slots_per_epoch", "slots_per_epoch = 32 ", "slots_per_epoch = 16 ", func(h HealthCheckConfig) bool { return h.SlotsPerEpoch == 16 }},
+		{"min_healthy_nodes", "min_healthy_nodes = 1 ", "min_healthy_nodes = 2 ", func(h HealthCheckConfig) bool { return h.MinHealthyNodes == 2 }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !strings.Contains(string(example), tc.line) {
+				t.Fatalf("Expected config.toml.example to contain %q", tc.line)
+			}
+			configPath := filepath.Join(t.TempDir(), "config.toml")
+			content := strings.Replace(string(example), tc.line, tc.changed, 1)
+			if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := Load(configPath)
+			if err != nil {
+				t.Fatalf("Failed to load the example: %v", err)
+			}
+			if !tc.check(cfg.HealthCheck) {
+				t.Errorf("Expected %q to be read from the [healthcheck] section", tc.changed)
+			}
+		})
+	}
+}
+
 func TestGetListenAddr(t *testing.T) {
 	cfg := &Config{
 		Server: ServerConfig{
@@ -316,6 +555,17 @@ func TestGetListenAddr(t *testing.T) {
 	}
 }
 
+func TestGetAdminListenAddr(t *testing.T) {
+	cfg := LoadOrDefault("")
+	if addr := cfg.GetAdminListenAddr(); addr != "127.0.0.1:8081" {
+		t.Errorf("Expected the admin API on loopback by default, got %s", addr)
+	}
+	cfg.Admin.Host = "::1"
+	if addr := cfg.GetAdminListenAddr(); addr != "[::1]:8081" {
+		t.Errorf("Expected an IPv6 admin address, got %s", addr)
+	}
+}
+
 func TestSetParsedNodes(t *testing.T) {
 	beacons := &BeaconsConfig{}
 
@@ -338,3 +588,51 @@ func TestSetParsedNodes(t *testing.T) {
 		t.Errorf("Expected second node URL=http://localhost:5053, got %s", beacons.parsedNodes[1].URL)
 	}
 }
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("[server]\nport = 8080\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	changes := make(chan struct{}, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go Watch(ctx, path, 10*time.Millisecond, func() {
+		changes <- struct{}{}
+	})
+
+	select {
+	case <-changes:
+		t.Fatal("Expected no reload for an unchanged file")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := os.WriteFile(path, []byte("[server]\nport = 19090\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	select {
+	case <-changes:
+	case <-time.After(time.Second):
+		t.Fatal("Expected a reload once the file changed")
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	before := getDefaultConfig()
+	after := getDefaultConfig()
+	after.Server.MaxRetries++
+	after.Server.ShutdownGracePeriod++
+	after.Reload.Watch = !after.Reload.Watch
+	after.RateLimit.RequestsPerSecond++
+	if settings := RestartRequired(before, after); len(settings) != 0 {
+		t.Errorf("Expected reloadable settings not to need a restart, got %v", settings)
+	}
+
+	after.Server.Port++
+	after.Events.DedupWindow++
+	settings := RestartRequired(before, after)
+	if len(settings) != 2 || settings[0] != "server listener" || settings[1] != "events" {
+		t.Errorf("Expected server listener and events to need a restart, got %v", settings)
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// Watch calls onChange whenever the modification time or size of the file at path changes,
+// checking every interval until ctx is done. A file that cannot be read is reported once it
+// is readable again.
+func Watch(ctx context.Context, path string, interval time.Duration, onChange func()) {
+	last, _ := os.Stat(path)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			last = nil
+			continue
+		}
+		if last == nil || !info.ModTime().Equal(last.ModTime()) || info.Size() != last.Size() {
+			last = info
+			onChange()
+		}
+	}
+}
+
+// RestartRequired lists the settings that differ between two configurations but are only
+// read at startup, so a reload cannot apply them
+func RestartRequired(before, after *Config) []string {
+	var settings []string
+	if before.Server.Port != after.Server.Port ||
+		before.Server.ReadTimeout != after.Server.ReadTimeout ||
+		before.Server.WriteTimeout != after.Server.WriteTimeout ||
+		before.Server.IdleTimeout != after.Server.IdleTimeout ||
+		before.Server.ReadHeaderTimeout != after.Server.ReadHeaderTimeout ||
+		before.Server.TLS != after.Server.TLS {
+		settings = append(settings, "server listener")
+	}
+	if before.Admin != after.Admin {
+		settings = append(settings, "admin")
+	}
+	if before.Metrics != after.Metrics {
+		settings = append(settings, "metrics")
+	}
+	if before.Logger != after.Logger {
+		settings = append(settings, "logger")
+	}
+	if before.WebSocket != after.WebSocket {
+		settings = append(settings, "websocket")
+	}
+	if before.Events != after.Events {
+		settings = append(settings, "events")
+	}
+	if before.HealthCheck.Interval != after.HealthCheck.Interval {
+		settings = append(settings, "health interval")
+	}
+	if before.HeadTracking.Enabled != after.HeadTracking.Enabled ||
+		before.HeadTracking.PollInterval != after.HeadTracking.PollInterval {
+		settings = append(settings, "head_tracking enabled and poll_interval")
+	}
+	if before.ForkDetection != after.ForkDetection {
+		settings = append(settings, "fork_detection")
+	}
+	return settings
+}
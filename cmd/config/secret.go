@@ -0,0 +1,162 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Prefixes of secret values that are read from elsewhere instead of the config file
+const (
+	secretEnvPrefix  = "env:"
+	secretFilePrefix = "file:"
+)
+
+// redacted replaces a secret wherever it would be printed
+const redacted = "[REDACTED]"
+
+// Secret is a credential sent to a beacon node. It prints, logs and marshals as [REDACTED]
+// so that it never ends up in logs or API responses; Value returns the credential itself.
+type Secret string
+
+// Value returns the secret in the clear
+func (s Secret) Value() string { return string(s) }
+
+// The remaining methods redact the secret wherever it is formatted, logged or marshaled
+func (s Secret) String() string                { return redacted }
+func (s Secret) GoString() string              { return redacted }
+func (s Secret) LogValue() slog.Value          { return slog.StringValue(redacted) }
+func (s Secret) MarshalJSON() ([]byte, error)  { return json.Marshal(redacted) }
+func (s Secret) MarshalText() ([]byte, error)  { return []byte(redacted), nil }
+func (s Secret) Format(f fmt.State, verb rune) { f.Write([]byte(redacted)) }
+
+// BasicAuthConfig contains HTTP basic authentication credentials for a beacon node
+type BasicAuthConfig struct {
+	Username string `toml:"username"`
+	Password Secret `toml:"password"`
+}
+
+// resolveSecret returns the secret a config value refers to: the contents of an environment
+// variable for `env:NAME`, of a file for `file:/path` with surrounding whitespace trimmed, or
+// the value itself otherwise. Errors never include the secret.
+func resolveSecret(value string) (Secret, error) {
+	switch {
+	case strings.HasPrefix(value, secretEnvPrefix):
+		name := strings.TrimPrefix(value, secretEnvPrefix)
+		secret, ok := os.LookupEnv(name)
+		if !ok || secret == "" {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return Secret(secret), nil
+	case strings.HasPrefix(value, secretFilePrefix):
+		path := strings.TrimPrefix(value, secretFilePrefix)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file %s: %v", path, err)
+		}
+		secret := strings.TrimSpace(string(data))
+		if secret == "" {
+			return "", fmt.Errorf("secret file %s is empty", path)
+		}
+		return Secret(secret), nil
+	default:
+		return Secret(value), nil
+	}
+}
+
+// parseNodeAuth parses a beacon's optional `headers`, `basic_auth` and `bearer_token`
+// settings, resolving secrets from the environment or files
+func parseNodeAuth(beaconConfig map[string]interface{}, node *NodeConfig) error {
+	if raw, ok := beaconConfig["headers"]; ok {
+		table, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("headers must be a table")
+		}
+		node.Headers = make(map[string]Secret, len(table))
+		for name, rawValue := range table {
+			value, ok := rawValue.(string)
+			if !ok {
+				return fmt.Errorf("header %s must be a string", name)
+			}
+			secret, err := resolveSecret(value)
+			if err != nil {
+				return fmt.Errorf("header %s: %v", name, err)
+			}
+			node.Headers[name] = secret
+		}
+	}
+
+	if raw, ok := beaconConfig["basic_auth"]; ok {
+		table, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("basic_auth must be a table")
+		}
+		username, _ := table["username"].(string)
+		password, _ := table["password"].(string)
+		secret, err := resolveSecret(password)
+		if err != nil {
+			return fmt.Errorf("basic_auth password: %v", err)
+		}
+		node.BasicAuth = &BasicAuthConfig{Username: username, Password: secret}
+	}
+
+	if raw, ok := beaconConfig["bearer_token"]; ok {
+		value, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("bearer_token must be a string")
+		}
+		secret, err := resolveSecret(value)
+		if err != nil {
+			return fmt.Errorf("bearer_token: %v", err)
+		}
+		node.BearerToken = secret
+	}
+	return nil
+}
+
+// validateNodeAuth checks that a node's credentials set the Authorization header at most once
+// and that its extra headers have valid names
+func validateNodeAuth(node NodeConfig) error {
+	if node.BasicAuth != nil && node.BasicAuth.Username == "" {
+		return fmt.Errorf("basic_auth requires a username")
+	}
+	authorization := 0
+	if node.BasicAuth != nil {
+		authorization++
+	}
+	if node.BearerToken != "" {
+		authorization++
+	}
+	for name := range node.Headers {
+		if !validHeaderName(name) {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+		switch http.CanonicalHeaderKey(name) {
+		case "Authorization":
+			authorization++
+		case "Host", "Connection", "Upgrade", "Content-Length", "Transfer-Encoding":
+			return fmt.Errorf("header %s cannot be set", name)
+		}
+	}
+	if authorization > 1 {
+		return fmt.Errorf("only one of basic_auth, bearer_token and an Authorization header can be set")
+	}
+	return nil
+}
+
+// validHeaderName reports whether name is a valid HTTP header field name
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if c > unicode.MaxASCII || !(unicode.IsLetter(c) || unicode.IsDigit(c) || strings.ContainsRune("!#$%&'*+-.^_`|~", c)) {
+			return false
+		}
+	}
+	return true
+}
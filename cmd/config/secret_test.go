@@ -0,0 +1,105 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoad_NodeCredentials(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "password")
+	if err := os.WriteFile(passwordFile, []byte("file-password\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_PROVIDER_KEY", "env-api-key")
+
+	configPath := filepath.Join(dir, "config.toml")
+	configContent := fmt.Sprintf(`
+[beacons]
+nodes = ["infura", "alchemy"]
+
+[beacons.infura]
+url = "https://beacon.example.com"
+type = "infura"
+
+[beacons.infura.headers]
+X-Api-Key = "env:TEST_PROVIDER_KEY"
+X-Client = "consensus-proxy"
+
+[beacons.infura.basic_auth]
+username = "project"
+password = "file:%s"
+
+[beacons.alchemy]
+url = "https://beacon.example.org"
+type = "alchemy"
+bearer_token = "literal-token"
+`, passwordFile)
+	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	nodes := cfg.GetAllNodes()
+
+	if got := nodes[0].Headers["X-Api-Key"].Value(); got != "env-api-key" {
+		t.Errorf("Expected the header read from the environment, got %q", got)
+	}
+	if got := nodes[0].Headers["X-Client"].Value(); got != "consensus-proxy" {
+		t.Errorf("Expected the literal header, got %q", got)
+	}
+	if nodes[0].BasicAuth == nil || nodes[0].BasicAuth.Username != "project" || nodes[0].BasicAuth.Password.Value() != "file-password" {
+		t.Errorf("Expected basic auth with the password read from the file, got %+v", nodes[0].BasicAuth)
+	}
+	if got := nodes[1].BearerToken.Value(); got != "literal-token" {
+		t.Errorf("Expected the literal bearer token, got %q", got)
+	}
+
+	// Secrets never show up when the configuration is printed, logged or marshaled
+	var logs bytes.Buffer
+	slog.New(slog.NewJSONHandler(&logs, nil)).Info("nodes", "node", nodes[0], "token", nodes[1].BearerToken)
+	marshaled, _ := json.Marshal(nodes)
+	for _, output := range []string{fmt.Sprintf("%v %+v %#v", nodes, nodes, nodes), logs.String(), string(marshaled)} {
+		for _, secret := range []string{"env-api-key", "file-password", "literal-token"} {
+			if strings.Contains(output, secret) {
+				t.Errorf("Secret %q leaked into %s", secret, output)
+			}
+		}
+	}
+}
+
+func TestLoad_NodeCredentialsErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		node string
+	}{
+		{"unset environment variable", `bearer_token = "env:TEST_UNSET_PROVIDER_KEY"`},
+		{"missing secret file", `bearer_token = "file:/nonexistent/token"`},
+		{"basic auth without username", "[beacons.test.basic_auth]\npassword = \"secret\""},
+		{"bearer token and basic auth", "bearer_token = \"token\"\n[beacons.test.basic_auth]\nusername = \"user\"\npassword = \"secret\""},
+		{"bearer token and authorization header", "bearer_token = \"token\"\n[beacons.test.headers]\nAuthorization = \"Bearer other\""},
+		{"host header", "[beacons.test.headers]\nHost = \"example.com\""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.toml")
+			configContent := "[beacons]\nnodes = [\"test\"]\n\n[beacons.test]\nurl = \"http://localhost:5052\"\n" + tc.node + "\n"
+			if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(configPath); err == nil {
+				t.Error("Expected the config to be rejected")
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package events
+
+import "encoding/json"
+
+// blockEventData holds the fields used to identify head and block events
+type blockEventData struct {
+	Slot  string `json:"slot"`
+	Block string `json:"block"`
+}
+
+// recentEvents remembers the most recently delivered head and block events so that
+// events replayed by a new upstream after failover are not delivered twice
+type recentEvents struct {
+	keys  map[string]struct{}
+	order []string
+	size  int
+}
+
+// newRecentEvents creates a dedup window holding up to size events
+func newRecentEvents(size int) *recentEvents {
+	return &recentEvents{
+		keys:  make(map[string]struct{}, size),
+		order: make([]string, 0, size),
+		size:  size,
+	}
+}
+
+// seen reports whether the event was already delivered and records it otherwise.
+// Only head and block events are tracked, keyed by event type, slot and block root.
+func (re *recentEvents) seen(event *Event) bool {
+	if re.size <= 0 || (event.Name != "head" && event.Name != "block") {
+		return false
+	}
+
+	var data blockEventData
+	if err := json.Unmarshal(event.Data, &data); err != nil || data.Slot == "" || data.Block == "" {
+		return false
+	}
+
+	key := event.Name + "/" + data.Slot + "/" + data.Block
+	if _, ok := re.keys[key]; ok {
+		return true
+	}
+
+	// Evict the oldest entry once the window is full
+	if len(re.order) >= re.size {
+		delete(re.keys, re.order[0])
+		re.order = re.order[1:]
+	}
+	re.keys[key] = struct{}{}
+	re.order = append(re.order, key)
+	return false
+}
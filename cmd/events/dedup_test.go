@@ -0,0 +1,43 @@
+package events
+
+import "testing"
+
+func TestRecentEvents_DropsDuplicates(t *testing.T) {
+	recent := newRecentEvents(2)
+
+	head := func(slot, root string) *Event {
+		return &Event{Name: "head", Data: []byte(`{"slot":"` + slot + `","block":"` + root + `"}`)}
+	}
+
+	if recent.seen(head("1", "0xaa")) {
+		t.Error("First occurrence should not be a duplicate")
+	}
+	if !recent.seen(head("1", "0xaa")) {
+		t.Error("Repeated head event should be a duplicate")
+	}
+	if recent.seen(head("1", "0xbb")) {
+		t.Error("Same slot with a different root (reorg) should not be a duplicate")
+	}
+	if recent.seen(&Event{Name: "block", Data: []byte(`{"slot":"1","block":"0xaa"}`)}) {
+		t.Error("Block event should be tracked separately from head events")
+	}
+
+	// Window of 2 has evicted the first entry by now
+	if recent.seen(head("1", "0xaa")) {
+		t.Error("Evicted event should no longer be considered a duplicate")
+	}
+}
+
+func TestRecentEvents_IgnoresOtherEvents(t *testing.T) {
+	recent := newRecentEvents(8)
+	event := &Event{Name: "finalized_checkpoint", Data: []byte(`{"block":"0xaa","epoch":"1"}`)}
+
+	if recent.seen(event) || recent.seen(event) {
+		t.Error("Only head and block events should be deduplicated")
+	}
+
+	malformed := &Event{Name: "head", Data: []byte(`not json`)}
+	if recent.seen(malformed) || recent.seen(malformed) {
+		t.Error("Events without slot and root should never be dropped")
+	}
+}
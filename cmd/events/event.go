@@ -0,0 +1,121 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Event represents a single Server-Sent Event from a beacon node's `/eth/v1/events` stream
+type Event struct {
+	Name string // event type, e.g. "head" or "finalized_checkpoint"
+	Data []byte // raw JSON payload
+	ID   string
+}
+
+// Encode serializes the event in text/event-stream wire format
+func (e *Event) Encode() []byte {
+	var buf bytes.Buffer
+	if e.ID != "" {
+		buf.WriteString("id: " + e.ID + "\n")
+	}
+	if e.Name != "" {
+		buf.WriteString("event: " + e.Name + "\n")
+	}
+	for _, line := range bytes.Split(e.Data, []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+// Reader parses Server-Sent Events from an upstream response body
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader creates a new event reader
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Next blocks until a complete event has been read.
+// Comment lines (heartbeats) are skipped. Returns io.EOF when the stream ends.
+func (er *Reader) Next() (*Event, error) {
+	event := &Event{}
+	var data [][]byte
+	hasFields := false
+
+	for {
+		line, err := er.r.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && line == "" {
+				return nil, io.EOF
+			}
+			if err != io.EOF {
+				return nil, err
+			}
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		// A blank line dispatches the event
+		if line == "" {
+			if hasFields {
+				event.Data = bytes.Join(data, []byte("\n"))
+				return event, nil
+			}
+			if err == io.EOF {
+				return nil, io.EOF
+			}
+			continue
+		}
+
+		// Comments are used as keep-alives
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			event.Name = value
+		case "data":
+			data = append(data, []byte(value))
+		case "id":
+			event.ID = value
+		default:
+			// Unknown fields (e.g. retry) are ignored
+			continue
+		}
+		hasFields = true
+
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+	}
+}
+
+// NormalizeTopics splits, deduplicates and sorts topics so that equivalent topic sets
+// share a single upstream subscription. Both `topics=a,b` and `topics=a&topics=b` are accepted.
+func NormalizeTopics(topics []string) []string {
+	seen := make(map[string]bool)
+	normalized := make([]string, 0, len(topics))
+	for _, value := range topics {
+		for _, topic := range strings.Split(value, ",") {
+			topic = strings.TrimSpace(topic)
+			if topic == "" || seen[topic] {
+				continue
+			}
+			seen[topic] = true
+			normalized = append(normalized, topic)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
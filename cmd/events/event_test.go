@@ -0,0 +1,94 @@
+package events
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReader_ParsesEvents(t *testing.T) {
+	stream := ": keep-alive\n" +
+		"event: head\n" +
+		"data: {\"slot\":\"1\"}\n" +
+		"\n" +
+		"id: 7\r\n" +
+		"event: finalized_checkpoint\r\n" +
+		"data: {\"epoch\":\"2\"}\r\n" +
+		"\r\n"
+
+	reader := NewReader(strings.NewReader(stream))
+
+	first, err := reader.Next()
+	if err != nil {
+		t.Fatalf("Expected first event, got error: %v", err)
+	}
+	if first.Name != "head" || string(first.Data) != `{"slot":"1"}` {
+		t.Errorf("Unexpected first event: %+v", first)
+	}
+
+	second, err := reader.Next()
+	if err != nil {
+		t.Fatalf("Expected second event, got error: %v", err)
+	}
+	if second.Name != "finalized_checkpoint" || second.ID != "7" || string(second.Data) != `{"epoch":"2"}` {
+		t.Errorf("Unexpected second event: %+v", second)
+	}
+
+	if _, err := reader.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF at end of stream, got %v", err)
+	}
+}
+
+func TestReader_MultiLineData(t *testing.T) {
+	reader := NewReader(strings.NewReader("event: block\ndata: line1\ndata: line2\n\n"))
+
+	event, err := reader.Next()
+	if err != nil {
+		t.Fatalf("Expected event, got error: %v", err)
+	}
+	if string(event.Data) != "line1\nline2" {
+		t.Errorf("Expected joined data lines, got %q", event.Data)
+	}
+}
+
+func TestReader_TruncatedEvent(t *testing.T) {
+	reader := NewReader(strings.NewReader("event: head\ndata: {\"slot\":"))
+
+	if _, err := reader.Next(); err != io.ErrUnexpectedEOF {
+		t.Errorf("Expected io.ErrUnexpectedEOF for truncated event, got %v", err)
+	}
+}
+
+func TestEvent_EncodeRoundTrip(t *testing.T) {
+	original := &Event{Name: "head", Data: []byte("a\nb"), ID: "42"}
+
+	decoded, err := NewReader(strings.NewReader(string(original.Encode()))).Next()
+	if err != nil {
+		t.Fatalf("Failed to decode encoded event: %v", err)
+	}
+	if !reflect.DeepEqual(original, decoded) {
+		t.Errorf("Round trip mismatch: got %+v, want %+v", decoded, original)
+	}
+}
+
+func TestNormalizeTopics(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    []string
+		expected []string
+	}{
+		{"single comma list", []string{"head,finalized_checkpoint"}, []string{"finalized_checkpoint", "head"}},
+		{"repeated parameters", []string{"head", "block"}, []string{"block", "head"}},
+		{"duplicates and spaces", []string{"head, head ,block", "block"}, []string{"block", "head"}},
+		{"empty", []string{"", " , "}, []string{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := NormalizeTopics(tc.input); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("NormalizeTopics(%v) = %v, want %v", tc.input, got, tc.expected)
+			}
+		})
+	}
+}
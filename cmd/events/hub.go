@@ -0,0 +1,471 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+	"github.com/zircuit-labs/consensus-proxy/cmd/logger"
+	"github.com/zircuit-labs/consensus-proxy/cmd/metrics"
+)
+
+var (
+	// ErrNoTopics is returned when a subscription does not name any topics
+	ErrNoTopics = errors.New("at least one event topic is required")
+	// ErrNoHealthyNodes is returned when there is no node to subscribe to
+	ErrNoHealthyNodes = errors.New("no healthy beacon nodes available")
+	// ErrUpstreamUnavailable is returned when every healthy node refused the subscription
+	ErrUpstreamUnavailable = errors.New("failed to establish event stream to any node")
+	// ErrHubClosed is returned when subscribing after the hub has been closed
+	ErrHubClosed = errors.New("event hub is shutting down")
+)
+
+// Reasons reported by Subscriber.Reason when a subscriber is closed by the hub
+const (
+	ReasonUnsubscribed   = "unsubscribed"
+	ReasonSlowConsumer   = "slow_consumer"
+	ReasonUpstreamClosed = "upstream_closed"
+	ReasonShutdown       = "shutdown"
+)
+
+// Hub multiplexes upstream beacon node event subscriptions to any number of clients.
+// Clients asking for the same topic set share one upstream connection, which is opened
+// for the first subscriber and closed when the last one leaves.
+type Hub struct {
+	mu            sync.Mutex
+	subscriptions map[string]*subscription
+	nodes         func() []*beaconnode.BeaconNode
+	metrics       metrics.Client
+	config        config.EventsConfig
+	maxAttempts   int
+	closed        bool // set by Close, guarded by mu
+}
+
+// subscription is a single upstream event stream shared by all clients with the same topics
+// and node pool
+type subscription struct {
+	key     string
+	name    string // the joined topics, used in logs and metrics
+	topics  []string
+	ready   chan struct{} // closed once the initial upstream connection attempt finishes
+	err     error         // set when the upstream could not be reached
+	node    *beaconnode.BeaconNode
+	clients map[*Subscriber]struct{}
+	cancel  context.CancelFunc
+	allow   func(*beaconnode.BeaconNode) bool // nodes the stream may be opened on, nil for any
+}
+
+// Subscriber receives events from a shared subscription through a bounded buffer
+type Subscriber struct {
+	hub    *Hub
+	sub    *subscription
+	events chan *Event
+	done   chan struct{}
+	once   sync.Once
+	reason string
+}
+
+// NewHub creates a new event hub.
+// nodes returns the candidate upstream nodes in preference order and maxAttempts caps
+// the nodes tried per connection attempt.
+func NewHub(nodes func() []*beaconnode.BeaconNode, cfg config.EventsConfig, maxAttempts int, metricsClient metrics.Client) *Hub {
+	return &Hub{
+		subscriptions: make(map[string]*subscription),
+		nodes:         nodes,
+		metrics:       metricsClient,
+		config:        cfg,
+		maxAttempts:   maxAttempts,
+	}
+}
+
+// Subscribe joins the shared upstream subscription for the given topics, opening it if needed
+func (h *Hub) Subscribe(topics []string) (*Subscriber, error) {
+	return h.SubscribeWithin(topics, "", nil)
+}
+
+// SubscribeWithin is like Subscribe, but the upstream stream is only opened on nodes that
+// allow accepts. Clients share a stream when they ask for the same topics and the same pool,
+// which names the set of nodes allow accepts.
+func (h *Hub) SubscribeWithin(topics []string, pool string, allow func(*beaconnode.BeaconNode) bool) (*Subscriber, error) {
+	topics = NormalizeTopics(topics)
+	if len(topics) == 0 {
+		return nil, ErrNoTopics
+	}
+	name := strings.Join(topics, ",")
+	key := name
+	if pool != "" {
+		key = name + "@" + pool
+	}
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil, ErrHubClosed
+	}
+	sub, exists := h.subscriptions[key]
+	if !exists {
+		ctx, cancel := context.WithCancel(context.Background())
+		sub = &subscription{
+			key:     key,
+			name:    name,
+			topics:  topics,
+			allow:   allow,
+			ready:   make(chan struct{}),
+			clients: make(map[*Subscriber]struct{}),
+			cancel:  cancel,
+		}
+		h.subscriptions[key] = sub
+		go h.run(ctx, sub)
+	}
+
+	client := &Subscriber{
+		hub:    h,
+		sub:    sub,
+		events: make(chan *Event, h.config.ClientBufferSize),
+		done:   make(chan struct{}),
+	}
+	sub.clients[client] = struct{}{}
+	subscriberCount := len(sub.clients)
+	h.mu.Unlock()
+
+	<-sub.ready
+	if sub.err != nil {
+		h.Unsubscribe(client)
+		return nil, sub.err
+	}
+
+	if h.metrics != nil {
+		h.metrics.Gauge("events.subscribers", float64(subscriberCount), []string{
+			fmt.Sprintf("topics:%s", name),
+		}, 1)
+	}
+
+	return client, nil
+}
+
+// Unsubscribe removes a client from its subscription. The upstream connection is
+// closed when the last client leaves.
+func (h *Hub) Unsubscribe(client *Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeLocked(client, ReasonUnsubscribed)
+}
+
+// removeLocked detaches a client and tears down the upstream once nobody is listening.
+// The caller must hold h.mu.
+func (h *Hub) removeLocked(client *Subscriber, reason string) {
+	sub := client.sub
+	if _, ok := sub.clients[client]; !ok {
+		return
+	}
+	delete(sub.clients, client)
+	client.close(reason)
+
+	if h.metrics != nil {
+		h.metrics.Gauge("events.subscribers", float64(len(sub.clients)), []string{
+			fmt.Sprintf("topics:%s", sub.name),
+		}, 1)
+	}
+
+	if len(sub.clients) == 0 && h.subscriptions[sub.key] == sub {
+		delete(h.subscriptions, sub.key)
+		sub.cancel()
+	}
+}
+
+// run connects the subscription upstream and broadcasts events until the last client
+// unsubscribes. If the upstream dies while clients are attached, the stream is migrated
+// to the next healthy node without disconnecting them.
+func (h *Hub) run(ctx context.Context, sub *subscription) {
+	reader, node, closeBody, err := h.connect(ctx, sub, nil)
+	if err != nil {
+		// Forget the failed subscription before waking waiters so later clients retry
+		h.mu.Lock()
+		if h.subscriptions[sub.key] == sub {
+			delete(h.subscriptions, sub.key)
+		}
+		h.mu.Unlock()
+		sub.err = err
+		close(sub.ready)
+		return
+	}
+	h.setNode(sub, node)
+	close(sub.ready)
+
+	logger.Info("upstream event subscription opened",
+		"node_name", node.Name,
+		"topics", sub.name,
+	)
+	if h.metrics != nil {
+		h.metrics.Incr("events.upstream_subscribed", []string{
+			fmt.Sprintf("node:%s", node.Name),
+		}, 1)
+	}
+
+	recent := newRecentEvents(h.config.DedupWindow)
+	for {
+		node.BeginStream()
+		err := h.stream(sub, reader, recent)
+		closeBody()
+		node.EndStream()
+
+		// The last client left; nothing to migrate
+		if ctx.Err() != nil {
+			h.closeSubscription(sub, node, ctx.Err())
+			return
+		}
+
+		logger.Warn("upstream event stream lost",
+			"node_name", node.Name,
+			"topics", sub.name,
+			"error", err,
+		)
+		node.IncrementError()
+
+		newReader, newNode, newCloseBody, reconnectErr := h.reconnect(ctx, sub, node)
+		if reconnectErr != nil {
+			h.closeSubscription(sub, node, fmt.Errorf("%v (reconnect failed: %v)", err, reconnectErr))
+			return
+		}
+
+		logger.Warn("event stream migrated to new node",
+			"from_node", node.Name,
+			"to_node", newNode.Name,
+			"topics", sub.name,
+			"reason", err.Error(),
+		)
+		if h.metrics != nil {
+			h.metrics.Incr("events.stream_migrated", []string{
+				fmt.Sprintf("from_node:%s", node.Name),
+				fmt.Sprintf("to_node:%s", newNode.Name),
+			}, 1)
+		}
+
+		reader, node, closeBody = newReader, newNode, newCloseBody
+		h.setNode(sub, node)
+	}
+}
+
+// stream broadcasts events from the upstream until it fails, dropping head and block
+// events that have already been delivered (e.g. replayed by a new node after failover)
+func (h *Hub) stream(sub *subscription, reader *Reader, recent *recentEvents) error {
+	for {
+		event, err := reader.Next()
+		if err != nil {
+			return err
+		}
+
+		if recent.seen(event) {
+			logger.Debug("dropping duplicate event",
+				"event", event.Name,
+				"topics", sub.name,
+			)
+			if h.metrics != nil {
+				h.metrics.Incr("events.duplicate_dropped", []string{
+					fmt.Sprintf("event:%s", event.Name),
+				}, 1)
+			}
+			continue
+		}
+
+		h.broadcast(sub, event)
+	}
+}
+
+// reconnect keeps trying to re-establish the upstream, preferring nodes other than
+// the one that just failed, until it succeeds or the reconnect timeout elapses
+func (h *Hub) reconnect(ctx context.Context, sub *subscription, failed *beaconnode.BeaconNode) (*Reader, *beaconnode.BeaconNode, func() error, error) {
+	deadline := time.Now().Add(h.config.ReconnectTimeout)
+
+	for {
+		reader, node, closeBody, err := h.connect(ctx, sub, failed)
+		if err == nil {
+			return reader, node, closeBody, nil
+		}
+		if ctx.Err() != nil || time.Now().Add(h.config.ReconnectBackoff).After(deadline) {
+			return nil, nil, nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, nil, ctx.Err()
+		case <-time.After(h.config.ReconnectBackoff):
+		}
+	}
+}
+
+// connect opens the upstream stream on the first healthy node that accepts it.
+// The avoid node, if any, is only tried after every other candidate.
+func (h *Hub) connect(ctx context.Context, sub *subscription, avoid *beaconnode.BeaconNode) (*Reader, *beaconnode.BeaconNode, func() error, error) {
+	candidates := h.nodes()
+	if sub.allow != nil {
+		allowed := make([]*beaconnode.BeaconNode, 0, len(candidates))
+		for _, node := range candidates {
+			if sub.allow(node) {
+				allowed = append(allowed, node)
+			}
+		}
+		candidates = allowed
+	}
+	if len(candidates) == 0 {
+		return nil, nil, nil, ErrNoHealthyNodes
+	}
+
+	nodes := make([]*beaconnode.BeaconNode, 0, len(candidates))
+	for _, node := range candidates {
+		if node != avoid {
+			nodes = append(nodes, node)
+		}
+	}
+	if len(nodes) < len(candidates) {
+		nodes = append(nodes, avoid)
+	}
+
+	for i, node := range nodes {
+		if h.maxAttempts > 0 && i >= h.maxAttempts {
+			break
+		}
+
+		node.IncrementRequests()
+		resp, err := node.SubscribeEvents(ctx, sub.topics)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, nil, nil, ctx.Err()
+			}
+			logger.Warn("upstream event subscription failed",
+				"node_name", node.Name,
+				"topics", sub.name,
+				"error", err,
+			)
+			node.IncrementError()
+			if h.metrics != nil {
+				h.metrics.Incr("events.upstream_failed", []string{
+					fmt.Sprintf("node:%s", node.Name),
+				}, 1)
+			}
+			continue
+		}
+
+		node.ResetErrors()
+		body := newIdleBody(resp.Body, h.config.IdleTimeout)
+		return NewReader(body), node, body.Close, nil
+	}
+
+	return nil, nil, nil, ErrUpstreamUnavailable
+}
+
+// setNode records the node currently serving a subscription
+func (h *Hub) setNode(sub *subscription, node *beaconnode.BeaconNode) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sub.node = node
+}
+
+// broadcast delivers an event to every client, dropping clients whose buffer is full
+func (h *Hub) broadcast(sub *subscription, event *Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for client := range sub.clients {
+		select {
+		case client.events <- event:
+		default:
+			logger.Warn("dropping slow event stream consumer",
+				"topics", sub.name,
+				"buffer_size", h.config.ClientBufferSize,
+			)
+			if h.metrics != nil {
+				h.metrics.Incr("events.client_dropped", []string{
+					fmt.Sprintf("topics:%s", sub.name),
+					"reason:" + ReasonSlowConsumer,
+				}, 1)
+			}
+			h.removeLocked(client, ReasonSlowConsumer)
+		}
+	}
+}
+
+// closeSubscription disconnects every remaining client once the upstream stream has ended
+func (h *Hub) closeSubscription(sub *subscription, node *beaconnode.BeaconNode, err error) {
+	h.mu.Lock()
+	clientCount := len(sub.clients)
+	for client := range sub.clients {
+		delete(sub.clients, client)
+		client.close(ReasonUpstreamClosed)
+	}
+	if h.subscriptions[sub.key] == sub {
+		delete(h.subscriptions, sub.key)
+	}
+	h.mu.Unlock()
+
+	logger.Info("upstream event subscription closed",
+		"node_name", node.Name,
+		"topics", sub.name,
+		"clients", clientCount,
+		"reason", err.Error(),
+	)
+	if h.metrics != nil {
+		h.metrics.Incr("events.upstream_unsubscribed", []string{
+			fmt.Sprintf("node:%s", node.Name),
+		}, 1)
+	}
+}
+
+// Close disconnects every client and closes their upstream subscriptions. Later subscriptions
+// fail with ErrHubClosed.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+
+	for _, sub := range h.subscriptions {
+		for client := range sub.clients {
+			h.removeLocked(client, ReasonShutdown)
+		}
+	}
+}
+
+// SubscriptionCount returns the number of open upstream subscriptions
+func (h *Hub) SubscriptionCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscriptions)
+}
+
+// Events returns the channel on which events are delivered
+func (s *Subscriber) Events() <-chan *Event {
+	return s.events
+}
+
+// Done is closed when the subscriber has been disconnected by the hub or unsubscribed
+func (s *Subscriber) Done() <-chan struct{} {
+	return s.done
+}
+
+// Reason returns why the subscriber was closed. Only valid after Done is closed.
+func (s *Subscriber) Reason() string {
+	return s.reason
+}
+
+// NodeName returns the name of the node currently serving the subscription
+func (s *Subscriber) NodeName() string {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	if s.sub.node == nil {
+		return ""
+	}
+	return s.sub.node.Name
+}
+
+// close marks the subscriber as finished with the given reason
+func (s *Subscriber) close(reason string) {
+	s.once.Do(func() {
+		s.reason = reason
+		close(s.done)
+	})
+}
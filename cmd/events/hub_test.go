@@ -0,0 +1,407 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+// mockEventServer emits a head event every interval and counts open subscriptions
+type mockEventServer struct {
+	*httptest.Server
+	subscriptions atomic.Int64
+	active        atomic.Int64
+}
+
+func newMockEventServer(interval time.Duration) *mockEventServer {
+	m := &mockEventServer{}
+	m.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/eth/v1/events" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		m.subscriptions.Add(1)
+		m.active.Add(1)
+		defer m.active.Add(-1)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		for slot := 1; ; slot++ {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(interval):
+			}
+			fmt.Fprintf(w, "event: head\ndata: {\"slot\":\"%d\"}\n\n", slot)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	return m
+}
+
+func newTestNode(t *testing.T, name, url string) *beaconnode.BeaconNode {
+	t.Helper()
+	cfg := config.LoadOrDefault("")
+	node, err := beaconnode.NewBeaconNode(config.NodeConfig{Name: name, URL: url}, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create beacon node: %v", err)
+	}
+	return node
+}
+
+func testEventsConfig(bufferSize int) config.EventsConfig {
+	cfg := config.LoadOrDefault("").Events
+	cfg.ClientBufferSize = bufferSize
+	return cfg
+}
+
+func waitFor(t *testing.T, condition func() bool, msg string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal(msg)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHub_SharesUpstreamSubscription(t *testing.T) {
+	server := newMockEventServer(10 * time.Millisecond)
+	defer server.Close()
+
+	node := newTestNode(t, "node1", server.URL)
+	hub := NewHub(func() []*beaconnode.BeaconNode { return []*beaconnode.BeaconNode{node} }, testEventsConfig(16), 3, nil)
+
+	first, err := hub.Subscribe([]string{"head,finalized_checkpoint"})
+	if err != nil {
+		t.Fatalf("First subscribe failed: %v", err)
+	}
+	second, err := hub.Subscribe([]string{"finalized_checkpoint", "head"})
+	if err != nil {
+		t.Fatalf("Second subscribe failed: %v", err)
+	}
+
+	for _, sub := range []*Subscriber{first, second} {
+		select {
+		case event := <-sub.Events():
+			if event.Name != "head" {
+				t.Errorf("Expected head event, got %q", event.Name)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for event")
+		}
+	}
+
+	if got := server.subscriptions.Load(); got != 1 {
+		t.Errorf("Expected 1 upstream subscription for equivalent topic sets, got %d", got)
+	}
+	if got := hub.SubscriptionCount(); got != 1 {
+		t.Errorf("Expected 1 hub subscription, got %d", got)
+	}
+
+	// The upstream stays open until the last client leaves
+	hub.Unsubscribe(first)
+	if got := hub.SubscriptionCount(); got != 1 {
+		t.Errorf("Expected subscription to survive while a client remains, got %d", got)
+	}
+
+	hub.Unsubscribe(second)
+	if got := hub.SubscriptionCount(); got != 0 {
+		t.Errorf("Expected subscription to close with the last client, got %d", got)
+	}
+	waitFor(t, func() bool { return server.active.Load() == 0 }, "Expected upstream connection to be closed")
+
+	if second.Reason() != ReasonUnsubscribed {
+		t.Errorf("Expected reason %q, got %q", ReasonUnsubscribed, second.Reason())
+	}
+}
+
+func TestHub_SubscribeWithin(t *testing.T) {
+	first := newMockEventServer(10 * time.Millisecond)
+	defer first.Close()
+	second := newMockEventServer(10 * time.Millisecond)
+	defer second.Close()
+
+	node1, node2 := newTestNode(t, "node1", first.URL), newTestNode(t, "node2", second.URL)
+	hub := NewHub(func() []*beaconnode.BeaconNode { return []*beaconnode.BeaconNode{node1, node2} }, testEventsConfig(16), 3, nil)
+
+	shared, err := hub.Subscribe([]string{"head"})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	defer hub.Unsubscribe(shared)
+	onlyNode2 := func(node *beaconnode.BeaconNode) bool { return node == node2 }
+	within, err := hub.SubscribeWithin([]string{"head"}, "node2", onlyNode2)
+	if err != nil {
+		t.Fatalf("SubscribeWithin failed: %v", err)
+	}
+	defer hub.Unsubscribe(within)
+
+	if shared.NodeName() != "node1" || within.NodeName() != "node2" {
+		t.Errorf("Expected streams on node1 and node2, got %q and %q", shared.NodeName(), within.NodeName())
+	}
+	if got := hub.SubscriptionCount(); got != 2 {
+		t.Errorf("Expected separate subscriptions per pool, got %d", got)
+	}
+
+	none := func(*beaconnode.BeaconNode) bool { return false }
+	if _, err := hub.SubscribeWithin([]string{"head"}, "empty", none); err != ErrNoHealthyNodes {
+		t.Errorf("Expected ErrNoHealthyNodes for a pool without nodes, got %v", err)
+	}
+}
+
+func TestHub_DropsSlowConsumer(t *testing.T) {
+	server := newMockEventServer(2 * time.Millisecond)
+	defer server.Close()
+
+	node := newTestNode(t, "node1", server.URL)
+	hub := NewHub(func() []*beaconnode.BeaconNode { return []*beaconnode.BeaconNode{node} }, testEventsConfig(2), 3, nil)
+
+	slow, err := hub.Subscribe([]string{"head"})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	fast, err := hub.Subscribe([]string{"head"})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	defer hub.Unsubscribe(fast)
+
+	// Keep the fast consumer drained while the slow one never reads
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case <-fast.Events():
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-slow.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected slow consumer to be dropped")
+	}
+
+	if slow.Reason() != ReasonSlowConsumer {
+		t.Errorf("Expected reason %q, got %q", ReasonSlowConsumer, slow.Reason())
+	}
+
+	select {
+	case <-fast.Done():
+		t.Errorf("Fast consumer should not be dropped (reason: %s)", fast.Reason())
+	default:
+	}
+}
+
+func TestHub_FailsOverOnSubscribe(t *testing.T) {
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer broken.Close()
+
+	server := newMockEventServer(10 * time.Millisecond)
+	defer server.Close()
+
+	primary := newTestNode(t, "primary", broken.URL)
+	backup := newTestNode(t, "backup", server.URL)
+	hub := NewHub(func() []*beaconnode.BeaconNode { return []*beaconnode.BeaconNode{primary, backup} }, testEventsConfig(16), 3, nil)
+
+	sub, err := hub.Subscribe([]string{"head"})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	defer hub.Unsubscribe(sub)
+
+	if sub.NodeName() != "backup" {
+		t.Errorf("Expected subscription on backup, got %q", sub.NodeName())
+	}
+	if errors, _, _ := primary.GetStats(); errors != 1 {
+		t.Errorf("Expected primary error count 1, got %d", errors)
+	}
+}
+
+func TestHub_SubscribeErrors(t *testing.T) {
+	hub := NewHub(func() []*beaconnode.BeaconNode { return nil }, testEventsConfig(16), 3, nil)
+
+	if _, err := hub.Subscribe(nil); err != ErrNoTopics {
+		t.Errorf("Expected ErrNoTopics, got %v", err)
+	}
+	if _, err := hub.Subscribe([]string{"head"}); err != ErrNoHealthyNodes {
+		t.Errorf("Expected ErrNoHealthyNodes, got %v", err)
+	}
+	if got := hub.SubscriptionCount(); got != 0 {
+		t.Errorf("Expected failed subscription to be forgotten, got %d", got)
+	}
+}
+
+// newScriptedEventServer emits head events for the given slots and then either closes
+// the stream or holds it open until the client leaves
+func newScriptedEventServer(slots []int, holdOpen bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		for _, slot := range slots {
+			fmt.Fprintf(w, "event: head\ndata: {\"slot\":\"%d\",\"block\":\"0x%02x\"}\n\n", slot, slot)
+			w.(http.Flusher).Flush()
+			time.Sleep(5 * time.Millisecond)
+		}
+		if holdOpen {
+			<-r.Context().Done()
+		}
+	}))
+}
+
+func TestHub_MigratesStreamOnUpstreamFailure(t *testing.T) {
+	// Primary dies after slot 3; backup replays slots 2-3 before continuing
+	primaryServer := newScriptedEventServer([]int{1, 2, 3}, false)
+	defer primaryServer.Close()
+	backupServer := newScriptedEventServer([]int{2, 3, 4, 5}, true)
+	defer backupServer.Close()
+
+	primary := newTestNode(t, "primary", primaryServer.URL)
+	backup := newTestNode(t, "backup", backupServer.URL)
+
+	cfg := testEventsConfig(16)
+	cfg.ReconnectBackoff = 10 * time.Millisecond
+	hub := NewHub(func() []*beaconnode.BeaconNode { return []*beaconnode.BeaconNode{primary, backup} }, cfg, 3, nil)
+
+	sub, err := hub.Subscribe([]string{"head"})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	defer hub.Unsubscribe(sub)
+
+	var slots []string
+	for len(slots) < 5 {
+		select {
+		case event := <-sub.Events():
+			var data blockEventData
+			if err := json.Unmarshal(event.Data, &data); err != nil {
+				t.Fatalf("Invalid event data %q: %v", event.Data, err)
+			}
+			slots = append(slots, data.Slot)
+		case <-sub.Done():
+			t.Fatalf("Client was disconnected during failover: %s", sub.Reason())
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for events, got slots %v", slots)
+		}
+	}
+
+	if want := []string{"1", "2", "3", "4", "5"}; !reflect.DeepEqual(slots, want) {
+		t.Errorf("Expected slots %v without duplicates, got %v", want, slots)
+	}
+	if sub.NodeName() != "backup" {
+		t.Errorf("Expected subscription to migrate to backup, got %q", sub.NodeName())
+	}
+}
+
+func TestHub_MigratesIdleStream(t *testing.T) {
+	// The primary sends one event, then goes silent without closing the connection
+	primaryServer := newScriptedEventServer([]int{1}, true)
+	defer primaryServer.Close()
+	backupServer := newMockEventServer(10 * time.Millisecond)
+	defer backupServer.Close()
+
+	primary := newTestNode(t, "primary", primaryServer.URL)
+	backup := newTestNode(t, "backup", backupServer.URL)
+
+	cfg := testEventsConfig(16)
+	cfg.ReconnectBackoff = 10 * time.Millisecond
+	cfg.IdleTimeout = 100 * time.Millisecond
+	hub := NewHub(func() []*beaconnode.BeaconNode { return []*beaconnode.BeaconNode{primary, backup} }, cfg, 3, nil)
+
+	sub, err := hub.Subscribe([]string{"head"})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	defer hub.Unsubscribe(sub)
+
+	waitFor(t, func() bool { return sub.NodeName() == "backup" }, "Expected the idle stream to migrate to the backup")
+	select {
+	case <-sub.Done():
+		t.Fatalf("Client was disconnected during migration: %s", sub.Reason())
+	default:
+	}
+}
+
+func TestHub_DisconnectsWhenReconnectTimesOut(t *testing.T) {
+	server := newScriptedEventServer([]int{1}, false)
+	defer server.Close()
+
+	node := newTestNode(t, "only", server.URL)
+	cfg := testEventsConfig(16)
+	cfg.ReconnectTimeout = 50 * time.Millisecond
+	cfg.ReconnectBackoff = 10 * time.Millisecond
+
+	// The node disappears from the healthy set once its stream dies
+	var healthy atomic.Bool
+	healthy.Store(true)
+	hub := NewHub(func() []*beaconnode.BeaconNode {
+		if healthy.Load() {
+			return []*beaconnode.BeaconNode{node}
+		}
+		return nil
+	}, cfg, 3, nil)
+
+	sub, err := hub.Subscribe([]string{"head"})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	healthy.Store(false)
+
+	select {
+	case <-sub.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected client to be disconnected once reconnecting gave up")
+	}
+
+	if sub.Reason() != ReasonUpstreamClosed {
+		t.Errorf("Expected reason %q, got %q", ReasonUpstreamClosed, sub.Reason())
+	}
+	if got := hub.SubscriptionCount(); got != 0 {
+		t.Errorf("Expected subscription to be removed, got %d", got)
+	}
+}
+
+func TestHub_Close(t *testing.T) {
+	server := newMockEventServer(10 * time.Millisecond)
+	defer server.Close()
+
+	node := newTestNode(t, "node1", server.URL)
+	hub := NewHub(func() []*beaconnode.BeaconNode { return []*beaconnode.BeaconNode{node} }, testEventsConfig(16), 3, nil)
+
+	sub, err := hub.Subscribe([]string{"head"})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	hub.Close()
+	select {
+	case <-sub.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the client to be disconnected on close")
+	}
+	if sub.Reason() != ReasonShutdown {
+		t.Errorf("Expected reason %q, got %q", ReasonShutdown, sub.Reason())
+	}
+	waitFor(t, func() bool { return server.active.Load() == 0 }, "Expected upstream connection to be closed")
+
+	if _, err := hub.Subscribe([]string{"head"}); err != ErrHubClosed {
+		t.Errorf("Expected ErrHubClosed, got %v", err)
+	}
+}
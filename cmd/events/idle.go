@@ -0,0 +1,47 @@
+package events
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// idleBody closes an upstream event stream once nothing, not even a heartbeat, has been read
+// from it for the idle timeout, so that a node that goes silent without closing the connection
+// fails like one that hung up and the stream is migrated
+type idleBody struct {
+	body    io.ReadCloser
+	timeout time.Duration
+	timer   *time.Timer
+	idle    atomic.Bool
+}
+
+// newIdleBody wraps body with an idle timeout, or returns it as is if timeout is 0
+func newIdleBody(body io.ReadCloser, timeout time.Duration) io.ReadCloser {
+	if timeout <= 0 {
+		return body
+	}
+	b := &idleBody{body: body, timeout: timeout}
+	b.timer = time.AfterFunc(timeout, func() {
+		b.idle.Store(true)
+		b.body.Close()
+	})
+	return b
+}
+
+func (b *idleBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	if n > 0 {
+		b.timer.Reset(b.timeout)
+	}
+	if err != nil && b.idle.Load() {
+		err = fmt.Errorf("no data from upstream for %s", b.timeout)
+	}
+	return n, err
+}
+
+func (b *idleBody) Close() error {
+	b.timer.Stop()
+	return b.body.Close()
+}
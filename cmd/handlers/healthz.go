@@ -3,7 +3,8 @@ package handlers
 import "net/http"
 
 // HealthzHandler responds with a simple JSON indicating the service is healthy.
-// This endpoint is for Kubernetes liveness/readiness probes and always returns 200 OK.
+// This endpoint is for Kubernetes liveness probes and always returns 200 OK; readiness
+// probes should use ReadyzHandler instead.
 // It bypasses the validator and all middleware.
 func HealthzHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/loadbalancer"
+)
+
+// ReadyzResponse is the body returned by `/readyz`
+type ReadyzResponse struct {
+	Status          string       `json:"status"` // ready or not_ready
+	ShuttingDown    bool         `json:"shutting_down"`
+	AvailableNodes  int          `json:"available_nodes"`
+	MinHealthyNodes int          `json:"min_healthy_nodes"`
+	Nodes           []ReadyzNode `json:"nodes"`
+}
+
+// ReadyzNode is whether a single node can take traffic
+type ReadyzNode struct {
+	Name      string `json:"name"`
+	Healthy   bool   `json:"healthy"`
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"` // why the node takes no traffic
+}
+
+// ReadyzHandler returns a handler for Kubernetes and load balancer readiness probes. It responds
+// 200 OK while enough nodes can take traffic, and 503 Service Unavailable otherwise or once
+// shutdown begins, listing every node's availability either way.
+// It bypasses the validator and all middleware.
+func ReadyzHandler(readiness func() loadbalancer.Readiness) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		state := readiness()
+		resp := ReadyzResponse{
+			Status:          "ready",
+			ShuttingDown:    state.ShuttingDown,
+			AvailableNodes:  state.AvailableNodes,
+			MinHealthyNodes: state.MinHealthyNodes,
+			Nodes:           make([]ReadyzNode, 0, len(state.Nodes)),
+		}
+		for _, node := range state.Nodes {
+			resp.Nodes = append(resp.Nodes, ReadyzNode(node))
+		}
+
+		status := http.StatusOK
+		if !state.Ready {
+			resp.Status = "not_ready"
+			status = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
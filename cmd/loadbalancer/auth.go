@@ -0,0 +1,268 @@
+package loadbalancer
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+	"github.com/zircuit-labs/consensus-proxy/cmd/logger"
+)
+
+// Outcomes of authenticating a request, reported in the auth.request metric
+const (
+	authAllowed      = "allowed"
+	authUnauthorized = "unauthorized"
+	authForbidden    = "forbidden"
+	authRateLimited  = "rate_limited"
+)
+
+// bearerPrefix precedes the key when clients present it in the Authorization header
+const bearerPrefix = "Bearer "
+
+// apiKeyContextKey is the request context key holding the authenticated *apiKey
+type apiKeyContextKey struct{}
+
+// apiKey is a client API key compiled from its configuration
+type apiKey struct {
+	name      string
+	key       []byte
+	endpoints []*regexp.Regexp // empty allows every endpoint
+	nodes     map[string]bool  // empty allows every node
+	pool      string           // the allowed node names joined, identifying shared event streams
+	limit     *keyRateLimit    // nil without a per-key rate limit
+}
+
+// keyRateLimit is a token bucket refilled with `rate` tokens per second, holding at most one
+// second's worth of requests
+type keyRateLimit struct {
+	mu     sync.Mutex
+	tokens float64
+	rate   float64
+	last   time.Time
+}
+
+// newKeyRateLimit creates a rate limit that starts with a full bucket
+func newKeyRateLimit(rate int) *keyRateLimit {
+	return &keyRateLimit{
+		tokens: float64(rate),
+		rate:   float64(rate),
+		last:   time.Now(),
+	}
+}
+
+// allow spends one token, returning false if the key is over its rate limit
+func (l *keyRateLimit) allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.tokens = min(l.rate, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// newAPIKeys compiles the configured API keys. A key keeps the rate limit state of the previous
+// key with the same name as long as its rate is unchanged, so that a reload does not refill it.
+func newAPIKeys(cfg config.AuthConfig, previous []*apiKey) ([]*apiKey, error) {
+	limits := make(map[string]*keyRateLimit, len(previous))
+	for _, key := range previous {
+		if key.limit != nil {
+			limits[key.name] = key.limit
+		}
+	}
+
+	keys := make([]*apiKey, 0, len(cfg.Keys))
+	for _, keyConfig := range cfg.Keys {
+		endpoints, err := compileEndpoints("auth key "+keyConfig.Name, keyConfig.Endpoints)
+		if err != nil {
+			return nil, err
+		}
+
+		key := &apiKey{
+			name:      keyConfig.Name,
+			key:       []byte(keyConfig.Key.Value()),
+			endpoints: endpoints,
+		}
+		if len(keyConfig.Nodes) > 0 {
+			key.nodes = make(map[string]bool, len(keyConfig.Nodes))
+			for _, node := range keyConfig.Nodes {
+				key.nodes[node] = true
+			}
+			pool := slices.Clone(keyConfig.Nodes)
+			slices.Sort(pool)
+			key.pool = strings.Join(slices.Compact(pool), ",")
+		}
+		if keyConfig.RateLimit > 0 {
+			if limit, ok := limits[keyConfig.Name]; ok && limit.rate == float64(keyConfig.RateLimit) {
+				key.limit = limit
+			} else {
+				key.limit = newKeyRateLimit(keyConfig.RateLimit)
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// allowsNode reports whether the key's requests may be served by node
+func (k *apiKey) allowsNode(node *beaconnode.BeaconNode) bool {
+	return len(k.nodes) == 0 || k.nodes[node.Name]
+}
+
+// allowsEndpoint reports whether the key may request path
+func (k *apiKey) allowsEndpoint(path string) bool {
+	return len(k.endpoints) == 0 || matchesEndpoint(k.endpoints, path)
+}
+
+// authenticate checks the API key presented with the request against the configured keys and
+// their policies, writing an error response and returning false if the request is refused. The
+// returned request carries the key in its context and no longer carries the key itself, so it
+// is never sent upstream.
+func (lb *LoadBalancer) authenticate(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	cfg := lb.config().Auth
+	presented, r := takeAPIKey(cfg, r)
+
+	key := lb.lookupAPIKey(presented)
+	if key == nil {
+		reason := "invalid API key"
+		if presented == "" {
+			reason = "missing API key"
+		}
+		lb.refuse(r, "", authUnauthorized, reason)
+		if cfg.IsAuthorizationHeader() {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+		}
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	if !key.allowsEndpoint(r.URL.Path) {
+		lb.refuse(r, key.name, authForbidden, "endpoint not allowed for API key")
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil, false
+	}
+
+	if key.limit != nil && !key.limit.allow() {
+		lb.refuse(r, key.name, authRateLimited, "API key rate limit exceeded")
+		http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+		return nil, false
+	}
+
+	lb.recordAuth(key.name, authAllowed)
+	return r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, key)), true
+}
+
+// takeAPIKey returns the API key presented in the auth header or, failing that, the auth query
+// parameter, along with a copy of the request without either of them
+func takeAPIKey(cfg config.AuthConfig, r *http.Request) (string, *http.Request) {
+	var presented string
+	if cfg.Header != "" {
+		presented = r.Header.Get(cfg.Header)
+		if cfg.IsAuthorizationHeader() {
+			if len(presented) > len(bearerPrefix) && strings.EqualFold(presented[:len(bearerPrefix)], bearerPrefix) {
+				presented = presented[len(bearerPrefix):]
+			} else {
+				presented = ""
+			}
+		}
+	}
+
+	query := r.URL.Query()
+	if presented == "" && cfg.QueryParam != "" {
+		presented = query.Get(cfg.QueryParam)
+	}
+
+	r = r.Clone(r.Context())
+	if cfg.Header != "" {
+		r.Header.Del(cfg.Header)
+	}
+	if cfg.QueryParam != "" && query.Has(cfg.QueryParam) {
+		query.Del(cfg.QueryParam)
+		r.URL.RawQuery = query.Encode()
+	}
+	return strings.TrimSpace(presented), r
+}
+
+// lookupAPIKey returns the configured key matching presented, or nil. Every key is compared in
+// constant time so that response timing does not reveal how much of a key was right.
+func (lb *LoadBalancer) lookupAPIKey(presented string) *apiKey {
+	if presented == "" {
+		return nil
+	}
+	var found *apiKey
+	for _, key := range lb.state.Load().apiKeys {
+		if subtle.ConstantTimeCompare(key.key, []byte(presented)) == 1 {
+			found = key
+		}
+	}
+	return found
+}
+
+// requestAPIKey returns the API key a request was authenticated with, or nil
+func requestAPIKey(r *http.Request) *apiKey {
+	key, _ := r.Context().Value(apiKeyContextKey{}).(*apiKey)
+	return key
+}
+
+// keyTag labels per-request metrics with the name of the request's API key, or "none" when
+// authentication is disabled
+func keyTag(r *http.Request) string {
+	name := "none"
+	if key := requestAPIKey(r); key != nil {
+		name = key.name
+	}
+	return fmt.Sprintf("key:%s", name)
+}
+
+// allowedNodes leaves out the nodes that the request's API key may not use
+func (lb *LoadBalancer) allowedNodes(nodes []*beaconnode.BeaconNode, r *http.Request) []*beaconnode.BeaconNode {
+	key := requestAPIKey(r)
+	if key == nil || len(key.nodes) == 0 {
+		return nodes
+	}
+
+	allowed := make([]*beaconnode.BeaconNode, 0, len(nodes))
+	for _, node := range nodes {
+		if key.allowsNode(node) {
+			allowed = append(allowed, node)
+		}
+	}
+	return allowed
+}
+
+// refuse logs a refused request without the key itself and records it in metrics
+func (lb *LoadBalancer) refuse(r *http.Request, keyName, result, reason string) {
+	logger.Warn("request refused by API key authentication",
+		"key_name", keyName,
+		"reason", reason,
+		"method", r.Method,
+		"path", r.URL.Path,
+		"remote_addr", r.RemoteAddr,
+	)
+	lb.recordAuth(keyName, result)
+}
+
+// recordAuth emits a metric for the outcome of authenticating a request
+func (lb *LoadBalancer) recordAuth(keyName, result string) {
+	if lb.metrics == nil {
+		return
+	}
+	if keyName == "" {
+		keyName = "unknown"
+	}
+	lb.metrics.Incr("auth.request", []string{
+		fmt.Sprintf("key:%s", keyName),
+		fmt.Sprintf("result:%s", result),
+	}, 1)
+}
@@ -0,0 +1,189 @@
+package loadbalancer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+// withAuth reloads lb with API key authentication enabled for keys
+func withAuth(t *testing.T, lb *LoadBalancer, auth config.AuthConfig) {
+	t.Helper()
+	cfg := *lb.config()
+	cfg.Auth = auth
+	if err := lb.Reload(&cfg); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	lb := newControlsLoadBalancer(t)
+	withAuth(t, lb, config.AuthConfig{
+		Enabled:    true,
+		Header:     "X-API-Key",
+		QueryParam: "api_key",
+		Keys: []config.APIKeyConfig{
+			{Name: "full", Key: "full-key"},
+			{Name: "limited", Key: "limited-key", Endpoints: []string{"^/eth/v1/node/"}, RateLimit: 2},
+			{Name: "backup-only", Key: "backup-key", Nodes: []string{"backup2"}},
+		},
+	})
+
+	testCases := []struct {
+		name       string
+		path       string
+		header     string
+		wantStatus int
+		wantBody   string
+	}{
+		{"missing key", "/eth/v1/node/version", "", http.StatusUnauthorized, ""},
+		{"unknown key", "/eth/v1/node/version", "wrong-key", http.StatusUnauthorized, ""},
+		{"key in header", "/eth/v1/node/version", "full-key", http.StatusOK, "primary"},
+		{"key in query", "/eth/v1/node/version?api_key=full-key", "", http.StatusOK, "primary"},
+		{"allowed endpoint", "/eth/v1/node/version", "limited-key", http.StatusOK, "primary"},
+		{"endpoint not allowed", "/eth/v1/beacon/genesis", "limited-key", http.StatusForbidden, ""},
+		{"restricted to a node", "/eth/v1/node/version", "backup-key", http.StatusOK, "backup2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.path, nil)
+			if tc.header != "" {
+				req.Header.Set("X-API-Key", tc.header)
+			}
+			w := httptest.NewRecorder()
+			lb.ServeHTTP(w, req)
+
+			if w.Code != tc.wantStatus {
+				t.Errorf("Expected status %d, got %d", tc.wantStatus, w.Code)
+			}
+			if tc.wantBody != "" && w.Body.String() != tc.wantBody {
+				t.Errorf("Expected the request to be served by %q, got %q", tc.wantBody, w.Body.String())
+			}
+		})
+	}
+
+	// The limited key has used one of its two requests per second above
+	statuses := make([]int, 0, 2)
+	for range 2 {
+		req := httptest.NewRequest("GET", "/eth/v1/node/version", nil)
+		req.Header.Set("X-API-Key", "limited-key")
+		w := httptest.NewRecorder()
+		lb.ServeHTTP(w, req)
+		statuses = append(statuses, w.Code)
+	}
+	if statuses[0] != http.StatusOK || statuses[1] != http.StatusTooManyRequests {
+		t.Errorf("Expected the limited key to be rate limited on its third request, got %v", statuses)
+	}
+
+	// Without authentication, requests pass through again
+	withAuth(t, lb, config.AuthConfig{})
+	if got := servedBy(lb); got != "primary" {
+		t.Errorf("Expected requests without a key to be served once auth is disabled, got %q", got)
+	}
+}
+
+func TestKeyTag(t *testing.T) {
+	lb := newControlsLoadBalancer(t)
+	if got := keyTag(httptest.NewRequest("GET", "/eth/v1/node/version", nil)); got != "key:none" {
+		t.Errorf("Expected requests without authentication to be tagged key:none, got %q", got)
+	}
+
+	withAuth(t, lb, config.AuthConfig{
+		Enabled: true,
+		Header:  "X-API-Key",
+		Keys:    []config.APIKeyConfig{{Name: "full", Key: "full-key"}},
+	})
+	req := httptest.NewRequest("GET", "/eth/v1/node/version", nil)
+	req.Header.Set("X-API-Key", "full-key")
+	req, ok := lb.authenticate(httptest.NewRecorder(), req)
+	if !ok {
+		t.Fatal("Expected the request to authenticate")
+	}
+	if got := keyTag(req); got != "key:full" {
+		t.Errorf("Expected the request to be tagged with its key name, got %q", got)
+	}
+}
+
+func TestAPIKeyRateLimitSurvivesReload(t *testing.T) {
+	lb := newControlsLoadBalancer(t)
+	auth := func(rateLimit int) config.AuthConfig {
+		return config.AuthConfig{
+			Enabled: true,
+			Header:  "X-API-Key",
+			Keys:    []config.APIKeyConfig{{Name: "limited", Key: "limited-key", RateLimit: rateLimit}},
+		}
+	}
+	request := func() int {
+		req := httptest.NewRequest("GET", "/eth/v1/node/version", nil)
+		req.Header.Set("X-API-Key", "limited-key")
+		w := httptest.NewRecorder()
+		lb.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	withAuth(t, lb, auth(2))
+	request()
+	request()
+
+	// An unchanged key keeps its spent tokens
+	withAuth(t, lb, auth(2))
+	if got := request(); got != http.StatusTooManyRequests {
+		t.Errorf("Expected the rate limit to carry over an unchanged reload, got %d", got)
+	}
+
+	// A new rate starts a fresh bucket
+	withAuth(t, lb, auth(3))
+	if got := request(); got != http.StatusOK {
+		t.Errorf("Expected a changed rate limit to start over, got %d", got)
+	}
+}
+
+func TestAuthenticate_KeyNotForwarded(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eth/v1/node/syncing" {
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+			return
+		}
+		received <- r
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := reloadConfig(config.NodeConfig{Name: "node", URL: server.URL, Type: "lighthouse"})
+	cfg.Auth = config.AuthConfig{
+		Enabled:    true,
+		Header:     "Authorization",
+		QueryParam: "api_key",
+		Keys:       []config.APIKeyConfig{{Name: "client", Key: "client-key"}},
+	}
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+
+	// An Authorization header only carries keys as bearer tokens
+	req := httptest.NewRequest("GET", "/eth/v1/node/version", nil)
+	req.Header.Set("Authorization", "client-key")
+	w := httptest.NewRecorder()
+	lb.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") != "Bearer" {
+		t.Errorf("Expected a bearer challenge for a key without the Bearer scheme, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/eth/v1/node/version?api_key=client-key&other=1", nil)
+	req.Header.Set("Authorization", "Bearer client-key")
+	lb.ServeHTTP(httptest.NewRecorder(), req)
+	upstream := <-received
+	if got := upstream.Header.Get("Authorization"); got != "" {
+		t.Errorf("Expected the Authorization header to be stripped, got %q", got)
+	}
+	if got := upstream.URL.RawQuery; got != "other=1" {
+		t.Errorf("Expected only the key to be stripped from the query, got %q", got)
+	}
+}
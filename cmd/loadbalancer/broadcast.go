@@ -0,0 +1,207 @@
+package loadbalancer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+	"github.com/zircuit-labs/consensus-proxy/cmd/logger"
+)
+
+// maxBroadcastErrorMessage caps the per-node error message included in aggregated errors
+const maxBroadcastErrorMessage = 256
+
+// broadcastResult holds a single node's answer to a broadcast submission
+type broadcastResult struct {
+	node     *beaconnode.BeaconNode
+	recorder *responseRecorder
+	duration time.Duration
+}
+
+// broadcastFailure describes why a node rejected a broadcast submission
+type broadcastFailure struct {
+	Node       string `json:"node"`
+	StatusCode int    `json:"status_code"`
+	Message    string `json:"message,omitempty"`
+}
+
+// broadcastErrorResponse is returned to the client when every node rejected the submission
+type broadcastErrorResponse struct {
+	Code     int                `json:"code"`
+	Message  string             `json:"message"`
+	Failures []broadcastFailure `json:"failures"`
+}
+
+// isBroadcastRequest reports whether the request is a submission that should be sent to every healthy node
+func (lb *LoadBalancer) isBroadcastRequest(r *http.Request) bool {
+	if !lb.config().Broadcast.Enabled || r.Method != http.MethodPost {
+		return false
+	}
+	return matchesEndpoint(lb.state.Load().broadcast, r.URL.Path)
+}
+
+// handleBroadcastRequest fans a submission out to all healthy nodes in parallel.
+// The first successful response is returned to the client immediately while the remaining
+// nodes finish in the background; if every node fails, an aggregated error is returned.
+func (lb *LoadBalancer) handleBroadcastRequest(w http.ResponseWriter, r *http.Request, start time.Time) {
+	body, ok := lb.readRequestBody(w, r)
+	if !ok {
+		return
+	}
+
+	healthyNodes := lb.eligibleNodes(lb.GetHealthyNodes(), r)
+	if len(healthyNodes) == 0 {
+		if lb.refuseUnsupportedMedia(w, r) {
+			return
+		}
+		http.Error(w, "No healthy beacon nodes available", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Nodes keep going after the client has its answer, so detach from the client context
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), lb.config().Broadcast.Timeout)
+	results := make(chan broadcastResult, len(healthyNodes))
+
+	for _, node := range healthyNodes {
+		go func(node *beaconnode.BeaconNode) {
+			recorder, duration := lb.attemptNodeRequest(nil, node, withReplayableBody(r.WithContext(ctx), body), lb.config().Broadcast.Timeout, 0)
+			results <- broadcastResult{node: node, recorder: recorder, duration: duration}
+		}(node)
+	}
+
+	failures := make([]broadcastResult, 0, len(healthyNodes))
+	for received := 0; received < len(healthyNodes); received++ {
+		result := <-results
+		lb.recordBroadcastResult(r, result)
+
+		if !result.recorder.isSuccess() {
+			failures = append(failures, result)
+			continue
+		}
+
+		// First success wins; collect the stragglers' outcomes in the background
+		result.recorder.copyToResponseWriter(w)
+		lb.logBroadcastOutcome(r, start, result.node.Name, result.recorder.statusCode, "success")
+
+		remaining := len(healthyNodes) - received - 1
+		go func() {
+			defer cancel()
+			for i := 0; i < remaining; i++ {
+				lb.recordBroadcastResult(r, <-results)
+			}
+		}()
+		return
+	}
+	cancel()
+
+	lb.writeBroadcastError(w, r, start, failures)
+}
+
+// recordBroadcastResult updates node state and emits per-node metrics for a broadcast attempt
+func (lb *LoadBalancer) recordBroadcastResult(r *http.Request, result broadcastResult) {
+	statusCode := result.recorder.statusCode
+	outcome := "success"
+
+	if result.recorder.isSuccess() {
+		result.node.ResetErrors()
+	} else {
+		outcome = "failure"
+		if statusCode >= HTTPStatusServerErrorMin || statusCode == 0 {
+			result.node.IncrementError()
+		}
+		logger.Warn("broadcast submission rejected by node",
+			"node_name", result.node.Name,
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status_code", statusCode,
+			"duration", result.duration.String(),
+		)
+	}
+
+	if lb.metrics != nil {
+		lb.metrics.Incr("broadcast.node_result", []string{
+			fmt.Sprintf("node:%s", result.node.Name),
+			fmt.Sprintf("result:%s", outcome),
+			fmt.Sprintf("status_code:%d", statusCode),
+		}, 1)
+		lb.metrics.Timing("broadcast.node_duration", result.duration, []string{
+			fmt.Sprintf("node:%s", result.node.Name),
+			fmt.Sprintf("result:%s", outcome),
+		}, 1)
+	}
+}
+
+// writeBroadcastError responds with an aggregated error listing every node's failure.
+// If every node rejected the submission as a client error, that status is passed through.
+func (lb *LoadBalancer) writeBroadcastError(w http.ResponseWriter, r *http.Request, start time.Time, failures []broadcastResult) {
+	statusCode := http.StatusBadGateway
+	allClientErrors := len(failures) > 0
+
+	response := broadcastErrorResponse{
+		Failures: make([]broadcastFailure, 0, len(failures)),
+	}
+	for _, failure := range failures {
+		code := failure.recorder.statusCode
+		if code < HTTPStatusClientErrorMin || code >= HTTPStatusServerErrorMin {
+			allClientErrors = false
+		}
+		response.Failures = append(response.Failures, broadcastFailure{
+			Node:       failure.node.Name,
+			StatusCode: code,
+			Message:    broadcastErrorMessage(failure.recorder.body),
+		})
+	}
+	if allClientErrors {
+		statusCode = failures[0].recorder.statusCode
+	}
+
+	response.Code = statusCode
+	response.Message = fmt.Sprintf("broadcast failed on all %d nodes", len(failures))
+
+	lb.logBroadcastOutcome(r, start, "all", statusCode, "failure")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
+
+// logBroadcastOutcome logs and records metrics for the response sent to the client
+func (lb *LoadBalancer) logBroadcastOutcome(r *http.Request, start time.Time, nodeName string, statusCode int, result string) {
+	totalDuration := time.Since(start)
+
+	logger.Info("broadcast request completed",
+		"method", r.Method,
+		"path", r.URL.Path,
+		"duration", totalDuration.String(),
+		"status_code", statusCode,
+		"node_used", nodeName,
+		"result", result,
+	)
+
+	if lb.metrics != nil {
+		lb.metrics.Timing("broadcast.duration", totalDuration, []string{
+			fmt.Sprintf("status_code:%d", statusCode),
+			fmt.Sprintf("result:%s", result),
+		}, 1)
+	}
+}
+
+// broadcastErrorMessage extracts a short error message from a node's error response,
+// preferring the Beacon API `message` field when the body is JSON
+func broadcastErrorMessage(body []byte) string {
+	var apiError struct {
+		Message string `json:"message"`
+	}
+	message := strings.TrimSpace(string(body))
+	if err := json.Unmarshal(body, &apiError); err == nil && apiError.Message != "" {
+		message = apiError.Message
+	}
+	if len(message) > maxBroadcastErrorMessage {
+		message = message[:maxBroadcastErrorMessage]
+	}
+	return message
+}
@@ -0,0 +1,181 @@
+package loadbalancer
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+// newBroadcastServer creates a beacon node that answers submissions with the given status
+// after an optional delay, recording every body it receives
+func newBroadcastServer(status int, delay time.Duration, received chan<- string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eth/v1/node/syncing" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		time.Sleep(delay)
+		received <- string(b)
+		w.WriteHeader(status)
+		if status >= HTTPStatusClientErrorMin {
+			w.Write([]byte(`{"code":` + strconv.Itoa(status) + `,"message":"rejected"}`))
+		}
+	}))
+}
+
+func newBroadcastLoadBalancer(t *testing.T, servers ...*httptest.Server) *LoadBalancer {
+	t.Helper()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Metrics.Enabled = false
+	cfg.Broadcast.Enabled = true
+	cfg.Broadcast.Timeout = 2 * time.Second
+
+	names := []string{"primary", "backup1", "backup2"}
+	nodes := make([]config.NodeConfig, 0, len(servers))
+	for i, server := range servers {
+		nodes = append(nodes, config.NodeConfig{Name: names[i], URL: server.URL, Type: "lighthouse"})
+	}
+	cfg.Beacons.Nodes = names[:len(servers)]
+	cfg.Beacons.SetParsedNodes(nodes)
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+	return lb
+}
+
+func TestBroadcastReturnsFirstSuccess(t *testing.T) {
+	const payload = `{"message":{"slot":"1"},"signature":"0xab"}`
+	received := make(chan string, 3)
+
+	// The primary is slow, so the first answer comes from a backup
+	primary := newBroadcastServer(http.StatusOK, 300*time.Millisecond, received)
+	defer primary.Close()
+	backup1 := newBroadcastServer(http.StatusOK, 0, received)
+	defer backup1.Close()
+	backup2 := newBroadcastServer(http.StatusServiceUnavailable, 0, received)
+	defer backup2.Close()
+
+	lb := newBroadcastLoadBalancer(t, primary, backup1, backup2)
+
+	req := httptest.NewRequest("POST", "/eth/v2/beacon/blocks", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	start := time.Now()
+	lb.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if elapsed := time.Since(start); elapsed >= 300*time.Millisecond {
+		t.Errorf("Expected the response before the slow primary finished, took %v", elapsed)
+	}
+
+	// Every node receives the submission, including the one still in flight
+	for i := 0; i < 3; i++ {
+		select {
+		case body := <-received:
+			if body != payload {
+				t.Errorf("Expected every node to receive the payload, got %q", body)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected 3 nodes to receive the submission, got %d", i)
+		}
+	}
+}
+
+func TestBroadcastAggregatesFailures(t *testing.T) {
+	testCases := []struct {
+		name         string
+		statuses     []int
+		expectedCode int
+	}{
+		{"all client errors", []int{http.StatusBadRequest, http.StatusBadRequest}, http.StatusBadRequest},
+		{"mixed errors", []int{http.StatusBadRequest, http.StatusInternalServerError}, http.StatusBadGateway},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			received := make(chan string, len(tc.statuses))
+			servers := make([]*httptest.Server, 0, len(tc.statuses))
+			for _, status := range tc.statuses {
+				server := newBroadcastServer(status, 0, received)
+				defer server.Close()
+				servers = append(servers, server)
+			}
+
+			lb := newBroadcastLoadBalancer(t, servers...)
+
+			req := httptest.NewRequest("POST", "/eth/v1/beacon/pool/voluntary_exits", strings.NewReader(`{}`))
+			w := httptest.NewRecorder()
+			lb.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedCode {
+				t.Fatalf("Expected status %d, got %d", tc.expectedCode, w.Code)
+			}
+
+			var response broadcastErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode aggregated error: %v", err)
+			}
+			if response.Code != tc.expectedCode {
+				t.Errorf("Expected code %d in body, got %d", tc.expectedCode, response.Code)
+			}
+			if len(response.Failures) != len(tc.statuses) {
+				t.Fatalf("Expected %d failures, got %d", len(tc.statuses), len(response.Failures))
+			}
+			for _, failure := range response.Failures {
+				if failure.Message != "rejected" {
+					t.Errorf("Expected node message to be extracted, got %q", failure.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestBroadcastOnlyForSubmissions(t *testing.T) {
+	server := newBroadcastServer(http.StatusOK, 0, make(chan string, 1))
+	defer server.Close()
+
+	lb := newBroadcastLoadBalancer(t, server)
+
+	testCases := []struct {
+		method    string
+		path      string
+		broadcast bool
+	}{
+		{"POST", "/eth/v1/beacon/pool/attestations", true},
+		{"POST", "/eth/v2/beacon/blinded_blocks", true},
+		{"POST", "/eth/v2/validator/aggregate_and_proofs", true},
+		{"GET", "/eth/v1/beacon/pool/attestations", false},
+		{"POST", "/eth/v1/validator/prepare_beacon_proposer", false},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		if got := lb.isBroadcastRequest(req); got != tc.broadcast {
+			t.Errorf("%s %s: expected broadcast=%v, got %v", tc.method, tc.path, tc.broadcast, got)
+		}
+	}
+
+	lb.config().Broadcast.Enabled = false
+	req := httptest.NewRequest("POST", "/eth/v1/beacon/pool/attestations", nil)
+	if lb.isBroadcastRequest(req) {
+		t.Error("Expected broadcast to be skipped when disabled")
+	}
+}
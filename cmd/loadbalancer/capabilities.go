@@ -0,0 +1,230 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+	"github.com/zircuit-labs/consensus-proxy/cmd/logger"
+)
+
+// Media types used by the Beacon Chain API
+const (
+	contentTypeJSON        = "application/json"
+	contentTypeSSZ         = "application/octet-stream"
+	contentTypeEventStream = "text/event-stream"
+)
+
+// lightClientEndpoints are only served by clients that implement the light client protocol
+var lightClientEndpoints = []string{
+	`^/eth/v1/beacon/light_client/.*$`,
+}
+
+// debugEndpoints expose full beacon states and fork choice internals
+var debugEndpoints = []string{
+	`^/eth/v[12]/debug/.*$`,
+}
+
+// builtinCapabilities is the built-in capability matrix of each beacon client type.
+// Client types that are not listed, and nodes without a type, are assumed to implement everything.
+var builtinCapabilities = map[string]config.CapabilityConfig{
+	"lighthouse": {ContentTypes: []string{contentTypeJSON, contentTypeSSZ, contentTypeEventStream}},
+	"teku":       {ContentTypes: []string{contentTypeJSON, contentTypeSSZ, contentTypeEventStream}},
+	"nimbus":     {ContentTypes: []string{contentTypeJSON, contentTypeSSZ, contentTypeEventStream}},
+	"prysm": {
+		UnsupportedEndpoints: lightClientEndpoints,
+		ContentTypes:         []string{contentTypeJSON, contentTypeSSZ, contentTypeEventStream},
+	},
+	"erigon": {
+		UnsupportedEndpoints: lightClientEndpoints,
+		ContentTypes:         []string{contentTypeJSON, contentTypeSSZ, contentTypeEventStream},
+	},
+	"infura": {
+		UnsupportedEndpoints: append(append([]string{}, lightClientEndpoints...), debugEndpoints...),
+		ContentTypes:         []string{contentTypeJSON, contentTypeEventStream},
+	},
+	"alchemy": {
+		UnsupportedEndpoints: append(append([]string{}, lightClientEndpoints...), debugEndpoints...),
+		ContentTypes:         []string{contentTypeJSON, contentTypeEventStream},
+	},
+}
+
+// capabilities describes which endpoints and content types a beacon client implements
+type capabilities struct {
+	unsupported  []*regexp.Regexp
+	contentTypes []string
+}
+
+// newCapabilities builds the capability matrix from the built-in table and the configured overrides
+func newCapabilities(overrides config.CapabilitiesConfig) (map[string]*capabilities, error) {
+	matrix := make(map[string]*capabilities, len(builtinCapabilities))
+	for clientType, builtin := range builtinCapabilities {
+		merged := builtin
+		if override, ok := overrides[clientType]; ok {
+			if override.UnsupportedEndpoints != nil {
+				merged.UnsupportedEndpoints = override.UnsupportedEndpoints
+			}
+			if override.ContentTypes != nil {
+				merged.ContentTypes = override.ContentTypes
+			}
+		}
+
+		unsupported, err := compileEndpoints(clientType+" unsupported", merged.UnsupportedEndpoints)
+		if err != nil {
+			return nil, err
+		}
+		contentTypes := make([]string, 0, len(merged.ContentTypes))
+		for _, contentType := range merged.ContentTypes {
+			contentTypes = append(contentTypes, strings.ToLower(contentType))
+		}
+
+		matrix[clientType] = &capabilities{
+			unsupported:  unsupported,
+			contentTypes: contentTypes,
+		}
+	}
+	return matrix, nil
+}
+
+// supports reports whether the client implements the request's endpoint, can read its body,
+// and can answer in one of the media types the request accepts
+func (c *capabilities) supports(r *http.Request) bool {
+	if matchesEndpoint(c.unsupported, r.URL.Path) {
+		return false
+	}
+	return c.readsBody(r) && c.accepts(r.Header.Get("Accept"))
+}
+
+// readsBody reports whether the client can read a body of the request's Content-Type. A missing
+// or unparsable header is left for the node to judge.
+func (c *capabilities) readsBody(r *http.Request) bool {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err != nil || c.hasContentType(mediaType)
+}
+
+// accepts reports whether the client can answer in one of the media types of an Accept header.
+// A missing or unparsable header accepts anything.
+func (c *capabilities) accepts(accept string) bool {
+	parsed := false
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		parsed = true
+
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q == 0 {
+			continue
+		}
+		if mediaType == "*/*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(mediaType, "*"); ok {
+			for _, contentType := range c.contentTypes {
+				if strings.HasPrefix(contentType, prefix) {
+					return true
+				}
+			}
+			continue
+		}
+		if c.hasContentType(mediaType) {
+			return true
+		}
+	}
+	return !parsed
+}
+
+// hasContentType reports whether the client handles the media type
+func (c *capabilities) hasContentType(mediaType string) bool {
+	for _, contentType := range c.contentTypes {
+		if contentType == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// supportingNodes leaves out nodes whose client type does not implement the request
+func (lb *LoadBalancer) supportingNodes(nodes []*beaconnode.BeaconNode, r *http.Request) []*beaconnode.BeaconNode {
+	supporting := make([]*beaconnode.BeaconNode, 0, len(nodes))
+	for _, node := range nodes {
+		if caps, ok := lb.state.Load().capabilities[node.Type]; ok && !caps.supports(r) {
+			if lb.metrics != nil {
+				lb.metrics.Incr("request.unsupported_skipped", []string{
+					fmt.Sprintf("node:%s", node.Name),
+					fmt.Sprintf("type:%s", node.Type),
+					keyTag(r),
+				}, 1)
+			}
+			continue
+		}
+		supporting = append(supporting, node)
+	}
+	return supporting
+}
+
+// unsupportedMediaStatus returns the status for a request that no healthy node can serve because
+// of its media types: 415 when none can read its body, 406 when none can answer in a media type
+// it accepts, and 0 when some node handles both or the request fails for another reason
+func (lb *LoadBalancer) unsupportedMediaStatus(r *http.Request) int {
+	nodes := lb.allowedNodes(lb.GetHealthyNodes(), r)
+	if len(nodes) == 0 {
+		return 0
+	}
+
+	readable, acceptable := false, false
+	for _, node := range nodes {
+		caps, ok := lb.state.Load().capabilities[node.Type]
+		if !ok {
+			return 0
+		}
+		readsBody, accepts := caps.readsBody(r), caps.accepts(r.Header.Get("Accept"))
+		if readsBody && accepts {
+			return 0
+		}
+		readable = readable || readsBody
+		acceptable = acceptable || accepts
+	}
+
+	if !readable {
+		return http.StatusUnsupportedMediaType
+	}
+	if !acceptable {
+		return http.StatusNotAcceptable
+	}
+	return 0
+}
+
+// refuseUnsupportedMedia answers with 415 or 406 when no healthy node supports the request's
+// media types, returning false if the request should fail as usual instead
+func (lb *LoadBalancer) refuseUnsupportedMedia(w http.ResponseWriter, r *http.Request) bool {
+	status := lb.unsupportedMediaStatus(r)
+	if status == 0 {
+		return false
+	}
+
+	logger.Warn("no healthy node supports the request's media type",
+		"method", r.Method,
+		"path", r.URL.Path,
+		"content_type", r.Header.Get("Content-Type"),
+		"accept", r.Header.Get("Accept"),
+		"status_code", status,
+	)
+	if lb.metrics != nil {
+		lb.metrics.Incr("request.unsupported_media", []string{
+			fmt.Sprintf("status_code:%d", status),
+			keyTag(r),
+		}, 1)
+	}
+	http.Error(w, http.StatusText(status), status)
+	return true
+}
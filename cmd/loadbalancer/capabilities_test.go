@@ -0,0 +1,161 @@
+package loadbalancer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+func TestCapabilitiesSupports(t *testing.T) {
+	matrix, err := newCapabilities(config.CapabilitiesConfig{
+		"teku": {UnsupportedEndpoints: []string{`^/eth/v1/beacon/rewards/.*$`}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to build capabilities: %v", err)
+	}
+
+	testCases := []struct {
+		name        string
+		clientType  string
+		path        string
+		accept      string
+		contentType string
+		expected    bool
+	}{
+		{name: "plain request", clientType: "prysm", path: "/eth/v1/node/version", expected: true},
+		{name: "light client on prysm", clientType: "prysm", path: "/eth/v1/beacon/light_client/finality_update", expected: false},
+		{name: "light client on lighthouse", clientType: "lighthouse", path: "/eth/v1/beacon/light_client/finality_update", expected: true},
+		{name: "debug state on hosted provider", clientType: "infura", path: "/eth/v2/debug/beacon/states/head", expected: false},
+		{name: "ssz only on hosted provider", clientType: "alchemy", path: "/eth/v2/beacon/blocks/head", accept: "application/octet-stream", expected: false},
+		{name: "ssz preferred on hosted provider", clientType: "alchemy", path: "/eth/v2/beacon/blocks/head", accept: "application/octet-stream;q=1.0,application/json;q=0.9", expected: true},
+		{name: "json refused", clientType: "alchemy", path: "/eth/v2/beacon/blocks/head", accept: "application/json;q=0,application/octet-stream", expected: false},
+		{name: "wildcard accept", clientType: "infura", path: "/eth/v2/beacon/blocks/head", accept: "application/*", expected: true},
+		{name: "ssz body on hosted provider", clientType: "infura", path: "/eth/v2/beacon/blocks", contentType: "application/octet-stream", expected: false},
+		{name: "ssz body on teku", clientType: "teku", path: "/eth/v2/beacon/blocks", contentType: "application/octet-stream", expected: true},
+		{name: "override", clientType: "teku", path: "/eth/v1/beacon/rewards/blocks/head", expected: false},
+		{name: "event stream on hosted provider", clientType: "infura", path: "/eth/v1/events", accept: "text/event-stream", expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tc.path, nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+			if tc.contentType != "" {
+				r.Header.Set("Content-Type", tc.contentType)
+			}
+			if supported := matrix[tc.clientType].supports(r); supported != tc.expected {
+				t.Errorf("Expected supports=%v, got %v", tc.expected, supported)
+			}
+		})
+	}
+}
+
+func TestUnsupportedNodesAreSkipped(t *testing.T) {
+	counts := make(map[string]int)
+	newServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/eth/v1/node/syncing" {
+				w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+				return
+			}
+			counts[name]++
+			w.WriteHeader(http.StatusOK)
+		}))
+	}
+	primary := newServer("primary")
+	defer primary.Close()
+	backup := newServer("backup")
+	defer backup.Close()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Metrics.Enabled = false
+	cfg.Beacons.Nodes = []string{"primary", "backup"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "primary", URL: primary.URL, Type: "prysm"},
+		{Name: "backup", URL: backup.URL, Type: "lighthouse"},
+	})
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	lb.ServeHTTP(w, httptest.NewRequest("GET", "/eth/v1/beacon/light_client/finality_update", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if counts["primary"] != 0 || counts["backup"] != 1 {
+		t.Errorf("Expected the light client request to skip the prysm primary, got %v", counts)
+	}
+
+	w = httptest.NewRecorder()
+	lb.ServeHTTP(w, httptest.NewRequest("GET", "/eth/v1/node/version", nil))
+	if counts["primary"] != 1 {
+		t.Errorf("Expected other requests to use the primary, got %v", counts)
+	}
+}
+
+func TestUnsupportedMediaIsRefused(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eth/v1/node/syncing" {
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Metrics.Enabled = false
+	cfg.Beacons.Nodes = []string{"infura"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "infura", URL: server.URL, Type: "infura"},
+	})
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+
+	testCases := []struct {
+		name        string
+		method      string
+		path        string
+		accept      string
+		contentType string
+		wantStatus  int
+	}{
+		{"ssz body", "POST", "/eth/v2/beacon/blocks", "", "application/octet-stream", http.StatusUnsupportedMediaType},
+		{"ssz response", "GET", "/eth/v2/beacon/blocks/head", "application/octet-stream", "", http.StatusNotAcceptable},
+		{"event stream", "GET", "/eth/v1/events", "text/event-stream", "", http.StatusOK},
+		{"unsupported endpoint", "GET", "/eth/v2/debug/beacon/states/head", "", "", http.StatusBadGateway},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+			if tc.contentType != "" {
+				r.Header.Set("Content-Type", tc.contentType)
+			}
+			w := httptest.NewRecorder()
+			lb.ServeHTTP(w, r)
+			if w.Code != tc.wantStatus {
+				t.Errorf("Expected status %d, got %d", tc.wantStatus, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package loadbalancer
+
+import (
+	"fmt"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+	"github.com/zircuit-labs/consensus-proxy/cmd/logger"
+)
+
+// watchCircuitBreaker logs and reports the state changes of a node's circuit breaker
+func (lb *LoadBalancer) watchCircuitBreaker(node *beaconnode.BeaconNode) {
+	node.Breaker.OnStateChange(func(from, to beaconnode.CircuitState) {
+		if to == beaconnode.CircuitOpen {
+			logger.Warn("circuit breaker opened - node taken out of rotation",
+				"node_name", node.Name,
+				"previous_state", from.String(),
+				"open_duration", lb.config().Breaker.OpenDuration,
+			)
+		} else {
+			logger.Info("circuit breaker state changed",
+				"node_name", node.Name,
+				"previous_state", from.String(),
+				"state", to.String(),
+			)
+		}
+
+		if lb.metrics != nil {
+			lb.metrics.Incr("circuit.state_change", []string{
+				fmt.Sprintf("node:%s", node.Name),
+				fmt.Sprintf("from:%s", from),
+				fmt.Sprintf("to:%s", to),
+			}, 1)
+			lb.metrics.Gauge("node.circuit_state", float64(to), []string{
+				fmt.Sprintf("node:%s", node.Name),
+			}, 1)
+		}
+	})
+}
+
+// skipOpenCircuits leaves out nodes whose circuit breaker is open or already has a probe in
+// flight. If no node is available, they are all returned rather than failing the request outright.
+func (lb *LoadBalancer) skipOpenCircuits(nodes []*beaconnode.BeaconNode) []*beaconnode.BeaconNode {
+	available := make([]*beaconnode.BeaconNode, 0, len(nodes))
+	for _, node := range nodes {
+		if node.Breaker.Available() {
+			available = append(available, node)
+		}
+	}
+
+	if len(available) == 0 {
+		return nodes
+	}
+	return available
+}
+
+// acquireCircuit asks the circuit breaker of the node about to be dialed to admit the request.
+// It returns whether the request may be sent, and whether the breaker admitted it and so must
+// be told its outcome. While no healthy node's circuit is available, requests are sent without
+// being admitted, like the fallback of skipOpenCircuits.
+func (lb *LoadBalancer) acquireCircuit(node *beaconnode.BeaconNode) (send, admitted bool) {
+	if node.Breaker.TryAcquire() {
+		return true, true
+	}
+	for _, healthy := range lb.GetHealthyNodes() {
+		if healthy.Breaker.Available() {
+			return false, false
+		}
+	}
+	return true, false
+}
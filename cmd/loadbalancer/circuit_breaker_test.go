@@ -0,0 +1,69 @@
+package loadbalancer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+func TestCircuitBreakerTakesFailingNodeOutOfRotation(t *testing.T) {
+	counts := make(map[string]int)
+	newServer := func(name string, status int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/eth/v1/node/syncing" {
+				w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+				return
+			}
+			counts[name]++
+			w.WriteHeader(status)
+		}))
+	}
+	primary := newServer("primary", http.StatusOK)
+	defer primary.Close()
+	backup := newServer("backup", http.StatusInternalServerError)
+	defer backup.Close()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Metrics.Enabled = false
+	cfg.Server.RequestTimeout = 5 * time.Second
+	cfg.Routing.Strategy = config.StrategyWeightedRoundRobin
+	cfg.Breaker.Enabled = true
+	cfg.Breaker.WindowSize = 4
+	cfg.Breaker.MinRequests = 4
+	cfg.Breaker.OpenDuration = time.Minute
+	cfg.Beacons.Nodes = []string{"primary", "backup"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "primary", URL: primary.URL, Type: "lighthouse"},
+		{Name: "backup", URL: backup.URL, Type: "lighthouse"},
+	})
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		w := httptest.NewRecorder()
+		lb.ServeHTTP(w, httptest.NewRequest("GET", "/eth/v1/node/version", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+	}
+
+	if state := lb.nodes()[1].Breaker.State(); state != beaconnode.CircuitOpen {
+		t.Fatalf("Expected the failing backup's circuit to be open, got %s", state)
+	}
+	if counts["backup"] != 4 {
+		t.Errorf("Expected the backup to leave rotation after 4 failures, got %d requests", counts["backup"])
+	}
+	if len(lb.GetHealthyNodes()) != 2 {
+		t.Errorf("Expected the periodic health state to be left alone, got %d healthy nodes", len(lb.GetHealthyNodes()))
+	}
+}
@@ -0,0 +1,177 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+	"github.com/zircuit-labs/consensus-proxy/cmd/logger"
+)
+
+// GetNode returns the node with the given name, or nil if there is none
+func (lb *LoadBalancer) GetNode(name string) *beaconnode.BeaconNode {
+	for _, node := range lb.nodes() {
+		if node.Name == name {
+			return node
+		}
+	}
+	return nil
+}
+
+// Cordon takes a node out of rotation: it gets no new requests, while requests and streams
+// already using it carry on. The node keeps being health checked and stays cordoned until
+// it is uncordoned.
+func (lb *LoadBalancer) Cordon(name string) error {
+	return lb.cordon(name, false)
+}
+
+// Drain cordons a node and marks it as draining, so that it reports being drained once its
+// in-flight requests, websockets and event streams have finished
+func (lb *LoadBalancer) Drain(name string) error {
+	return lb.cordon(name, true)
+}
+
+// cordon takes a node out of rotation, optionally draining it
+func (lb *LoadBalancer) cordon(name string, drain bool) error {
+	node := lb.GetNode(name)
+	if node == nil {
+		return fmt.Errorf("unknown node: %s", name)
+	}
+
+	node.Cordon(drain)
+	logger.Info("node cordoned by operator",
+		"node_name", node.Name,
+		"drain", drain,
+		"outstanding", node.GetOutstanding(),
+		"streams", node.GetStreams(),
+	)
+	action := "cordon"
+	if drain {
+		action = "drain"
+	}
+	lb.recordControl(action, node)
+	return nil
+}
+
+// Uncordon puts a cordoned or draining node back into rotation
+func (lb *LoadBalancer) Uncordon(name string) error {
+	node := lb.GetNode(name)
+	if node == nil {
+		return fmt.Errorf("unknown node: %s", name)
+	}
+
+	node.Uncordon()
+	logger.Info("node uncordoned by operator",
+		"node_name", node.Name,
+	)
+	lb.recordControl("uncordon", node)
+	return nil
+}
+
+// ForcePrimary promotes a healthy, uncordoned node to primary and keeps it as the preferred
+// primary until the override is cleared: automatic failback and priority restoration treat it
+// the way they would otherwise treat the original primary. The displaced primary takes the
+// promoted node's original priority.
+func (lb *LoadBalancer) ForcePrimary(name string) error {
+	node := lb.GetNode(name)
+	if node == nil {
+		return fmt.Errorf("unknown node: %s", name)
+	}
+	if node.IsCordoned() {
+		return fmt.Errorf("node %s is cordoned", name)
+	}
+
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	if !slices.Contains(lb.healthyNodes, node) {
+		return fmt.Errorf("node %s is not healthy", name)
+	}
+
+	lb.manualPrimary.Store(node)
+	for _, other := range lb.nodes() {
+		if other != node && other.IsPrimary() {
+			other.SetPriority(lb.restingPriority(other))
+			logger.Info("primary demoted by operator",
+				"node_name", other.Name,
+				"restored_priority", other.GetPriority(),
+			)
+		}
+	}
+	node.SetPriority(0)
+	node.ResetErrors()
+
+	// Keep the healthy list in priority order, without reordering the slice readers may hold
+	healthy := slices.Clone(lb.healthyNodes)
+	slices.SortStableFunc(healthy, func(a, b *beaconnode.BeaconNode) int {
+		return a.GetPriority() - b.GetPriority()
+	})
+	lb.healthyNodes = healthy
+
+	logger.Info("node promoted to primary by operator",
+		"node_name", node.Name,
+		"original_priority", node.OriginalPriority,
+	)
+	lb.recordControl("promote", node)
+	return nil
+}
+
+// ClearPrimaryOverride drops the operator's choice of primary. The promoted node stays primary
+// until the original primary fails back through the regular health checks.
+func (lb *LoadBalancer) ClearPrimaryOverride() {
+	node := lb.manualPrimary.Swap(nil)
+	if node == nil {
+		return
+	}
+
+	logger.Info("primary override cleared by operator",
+		"node_name", node.Name,
+	)
+	lb.recordControl("clear_promote", node)
+}
+
+// ManualPrimary returns the node promoted by an operator, or nil if there is no override
+func (lb *LoadBalancer) ManualPrimary() *beaconnode.BeaconNode {
+	return lb.manualPrimary.Load()
+}
+
+// isPreferredPrimary reports whether a node should be primary whenever it is healthy: the node
+// promoted by an operator, or the original primary without an override
+func (lb *LoadBalancer) isPreferredPrimary(node *beaconnode.BeaconNode) bool {
+	if manual := lb.manualPrimary.Load(); manual != nil {
+		return node == manual
+	}
+	return node.OriginalPriority == 0
+}
+
+// restingPriority returns the priority a node goes back to when it is not primary. While an
+// override is set, the original primary takes the promoted node's place.
+func (lb *LoadBalancer) restingPriority(node *beaconnode.BeaconNode) int {
+	if manual := lb.manualPrimary.Load(); manual != nil && manual != node && node.OriginalPriority == 0 {
+		return manual.OriginalPriority
+	}
+	return node.OriginalPriority
+}
+
+// skipCordonedNodes leaves out nodes cordoned by an operator. Like forked nodes, they are
+// never used as a fallback.
+func (lb *LoadBalancer) skipCordonedNodes(nodes []*beaconnode.BeaconNode) []*beaconnode.BeaconNode {
+	routable := make([]*beaconnode.BeaconNode, 0, len(nodes))
+	for _, node := range nodes {
+		if !node.IsCordoned() {
+			routable = append(routable, node)
+		}
+	}
+	return routable
+}
+
+// recordControl emits a metric for an operator action on a node
+func (lb *LoadBalancer) recordControl(action string, node *beaconnode.BeaconNode) {
+	if lb.metrics == nil {
+		return
+	}
+	lb.metrics.Incr("node.operator_action", []string{
+		fmt.Sprintf("node:%s", node.Name),
+		fmt.Sprintf("action:%s", action),
+	}, 1)
+}
@@ -0,0 +1,165 @@
+package loadbalancer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+// newControlsLoadBalancer creates a load balancer with three synced nodes that answer
+// non health check requests with their name
+func newControlsLoadBalancer(t *testing.T) *LoadBalancer {
+	t.Helper()
+
+	names := []string{"primary", "backup1", "backup2"}
+	nodes := make([]config.NodeConfig, len(names))
+	for i, name := range names {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/eth/v1/node/syncing" {
+				w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+				return
+			}
+			w.Write([]byte(name))
+		}))
+		t.Cleanup(server.Close)
+		nodes[i] = config.NodeConfig{Name: name, URL: server.URL, Type: "lighthouse"}
+	}
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Metrics.Enabled = false
+	cfg.HealthCheck.SuccessfulChecksForFailback = 2
+	cfg.Beacons.Nodes = names
+	cfg.Beacons.SetParsedNodes(nodes)
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+	return lb
+}
+
+// servedBy returns the name of the node that served a request
+func servedBy(lb *LoadBalancer) string {
+	w := httptest.NewRecorder()
+	lb.ServeHTTP(w, httptest.NewRequest("GET", "/eth/v1/node/version", nil))
+	return w.Body.String()
+}
+
+func TestCordon(t *testing.T) {
+	lb := newControlsLoadBalancer(t)
+
+	if err := lb.Cordon("primary"); err != nil {
+		t.Fatalf("Cordon failed: %v", err)
+	}
+	lb.performHealthCheck()
+	if got := servedBy(lb); got != "backup1" {
+		t.Errorf("Expected a cordoned primary to be skipped, got request served by %q", got)
+	}
+	if !lb.nodes()[0].IsPrimary() {
+		t.Error("Expected cordoning to leave priorities alone")
+	}
+
+	if err := lb.Uncordon("primary"); err != nil {
+		t.Fatalf("Uncordon failed: %v", err)
+	}
+	if got := servedBy(lb); got != "primary" {
+		t.Errorf("Expected an uncordoned primary to serve again, got %q", got)
+	}
+
+	if err := lb.Cordon("missing"); err == nil {
+		t.Error("Expected an error cordoning an unknown node")
+	}
+}
+
+func TestDrain(t *testing.T) {
+	lb := newControlsLoadBalancer(t)
+	node := lb.nodes()[1]
+
+	node.BeginRequest()
+	node.BeginStream()
+	if err := lb.Drain("backup1"); err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	if !node.IsCordoned() || !node.IsDraining() || node.IsDrained() {
+		t.Fatal("Expected a draining node with in-flight work not to be drained")
+	}
+
+	node.EndRequest()
+	if node.IsDrained() {
+		t.Fatal("Expected a node with an open stream not to be drained")
+	}
+	node.EndStream()
+	if !node.IsDrained() {
+		t.Fatal("Expected the node to be drained once its requests and streams finished")
+	}
+
+	lb.Uncordon("backup1")
+	if node.IsCordoned() || node.IsDraining() {
+		t.Error("Expected uncordon to end the drain")
+	}
+}
+
+func TestForcePrimary(t *testing.T) {
+	lb := newControlsLoadBalancer(t)
+	primary, backup1, backup2 := lb.nodes()[0], lb.nodes()[1], lb.nodes()[2]
+
+	if err := lb.ForcePrimary("backup2"); err != nil {
+		t.Fatalf("ForcePrimary failed: %v", err)
+	}
+	if !backup2.IsPrimary() || primary.GetPriority() != 2 {
+		t.Fatalf("Expected backup2 promoted and primary at priority 2, got primary=%d backup2=%d", primary.GetPriority(), backup2.GetPriority())
+	}
+	if got := servedBy(lb); got != "backup2" {
+		t.Errorf("Expected the promoted node to serve, got %q", got)
+	}
+
+	// The override survives automatic failback and priority restoration
+	for i := 0; i < 3; i++ {
+		lb.performHealthCheck()
+	}
+	if !backup2.IsPrimary() || primary.GetPriority() != 2 || backup1.GetPriority() != 1 {
+		t.Fatalf("Expected the override to survive health checks, got primary=%d backup1=%d backup2=%d", primary.GetPriority(), backup1.GetPriority(), backup2.GetPriority())
+	}
+	if got := servedBy(lb); got != "backup2" {
+		t.Errorf("Expected the promoted node to keep serving, got %q", got)
+	}
+
+	// Once cleared, the original primary fails back
+	lb.ClearPrimaryOverride()
+	if lb.ManualPrimary() != nil {
+		t.Fatal("Expected no primary override")
+	}
+	lb.performHealthCheck()
+	if !primary.IsPrimary() || backup2.GetPriority() != 2 {
+		t.Fatalf("Expected failback to the original primary, got primary=%d backup2=%d", primary.GetPriority(), backup2.GetPriority())
+	}
+}
+
+func TestForcePrimary_Rejected(t *testing.T) {
+	lb := newControlsLoadBalancer(t)
+	lb.Cordon("backup1")
+
+	testCases := []struct {
+		name string
+		node string
+	}{
+		{"unknown node", "missing"},
+		{"cordoned node", "backup1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := lb.ForcePrimary(tc.node); err == nil {
+				t.Errorf("Expected promoting %s to fail", tc.node)
+			}
+			if !lb.nodes()[0].IsPrimary() || lb.ManualPrimary() != nil {
+				t.Error("Expected the primary to be left alone")
+			}
+		})
+	}
+}
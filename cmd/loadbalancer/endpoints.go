@@ -0,0 +1,31 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// compileEndpoints compiles the endpoint patterns of a request mode such as broadcast or hedging
+func compileEndpoints(mode string, endpoints []string) ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		pattern, err := regexp.Compile(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s endpoint pattern %q: %v", mode, endpoint, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// matchesEndpoint reports whether the path, ignoring trailing slashes, matches any of the patterns
+func matchesEndpoint(patterns []*regexp.Regexp, path string) bool {
+	path = strings.TrimRight(path, "/")
+	for _, pattern := range patterns {
+		if pattern.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
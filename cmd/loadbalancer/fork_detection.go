@@ -0,0 +1,291 @@
+package loadbalancer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+	"github.com/zircuit-labs/consensus-proxy/cmd/logger"
+)
+
+// Kinds of root compared by fork detection
+const (
+	forkKindHead      = "head"
+	forkKindFinalized = "finalized"
+)
+
+// StartForkDetection starts a background goroutine that periodically compares the nodes' head
+// and finalized roots until ctx is done, quarantining nodes that are on a minority fork
+func (lb *LoadBalancer) StartForkDetection(ctx context.Context) {
+	if !lb.config().ForkDetection.Enabled {
+		return
+	}
+
+	ticker := time.NewTicker(lb.config().ForkDetection.Interval)
+
+	go func() {
+		defer ticker.Stop()
+		lb.detectForks()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				lb.detectForks()
+			}
+		}
+	}()
+
+	logger.Info("started fork detection routine",
+		"interval", lb.config().ForkDetection.Interval,
+	)
+}
+
+// rootVote is the root a node reported for a slot or epoch
+type rootVote struct {
+	node *beaconnode.BeaconNode
+	root string
+}
+
+// detectForks compares every node's block roots `depth` slots behind the lowest head slot across
+// nodes, so that all of them have reached it and a reorg at the tip does not count, and the
+// finalized roots of nodes that finalized the same epoch. A node that disagrees with a strict
+// majority in `confirmations` consecutive comparisons is quarantined from routing until it
+// agrees again, unless that would leave no healthy node in rotation. Nodes that cannot be
+// compared this round keep their previous state.
+func (lb *LoadBalancer) detectForks() {
+	cfg := lb.config().ForkDetection
+	timeout := lb.config().HealthCheck.Timeout
+	nodes := lb.nodes()
+	heads := make([]uint64, len(nodes))
+	headKnown := make([]bool, len(nodes))
+	finalized := make([]uint64, len(nodes))
+	finalizedRoots := make([]string, len(nodes))
+	finalizedKnown := make([]bool, len(nodes))
+
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if slot, _, err := node.FetchHead(timeout); err == nil {
+				heads[i], headKnown[i] = slot, true
+			}
+			if epoch, root, err := node.FetchFinalizedCheckpoint(timeout); err == nil {
+				finalized[i], finalizedRoots[i], finalizedKnown[i] = epoch, root, true
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Compare block roots depth slots behind the lowest head slot
+	var slot uint64
+	slotKnown := false
+	for i := range nodes {
+		if headKnown[i] && (!slotKnown || heads[i] < slot) {
+			slot, slotKnown = heads[i], true
+		}
+	}
+	slot -= min(slot, cfg.Depth)
+
+	verdicts := make(map[*beaconnode.BeaconNode]bool)
+	if slotKnown {
+		headVotes := make([]rootVote, len(nodes))
+		for i, node := range nodes {
+			if !headKnown[i] {
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				root, err := node.FetchBlockRoot(slot, timeout)
+				if err != nil {
+					logger.Debug("failed to fetch block root for fork detection",
+						"node_name", node.Name,
+						"slot", slot,
+						"error", err,
+					)
+					return
+				}
+				headVotes[i] = rootVote{node: node, root: root}
+			}()
+		}
+		wg.Wait()
+		lb.compareRoots(forkKindHead, slot, headVotes, verdicts)
+	}
+
+	// Compare finalized roots of nodes at the same finalized epoch
+	byEpoch := make(map[uint64][]rootVote)
+	for i, node := range nodes {
+		if finalizedKnown[i] {
+			byEpoch[finalized[i]] = append(byEpoch[finalized[i]], rootVote{node: node, root: finalizedRoots[i]})
+		}
+	}
+	for epoch, votes := range byEpoch {
+		lb.compareRoots(forkKindFinalized, epoch, votes, verdicts)
+	}
+
+	// A node is only quarantined once it has disagreed in enough consecutive comparisons
+	quarantine := make(map[*beaconnode.BeaconNode]bool, len(verdicts))
+	for node, disagreed := range verdicts {
+		quarantine[node] = node.RecordForkVerdict(disagreed) >= cfg.Confirmations
+	}
+	if !lb.keepsHealthyNode(quarantine) {
+		logger.Error("fork detection would quarantine every healthy node - keeping them in rotation")
+		for node := range quarantine {
+			quarantine[node] = false
+		}
+	}
+
+	for node, forked := range quarantine {
+		if node.SetForked(forked) {
+			if forked {
+				logger.Error("node is on a minority fork - quarantined from routing",
+					"node_name", node.Name,
+					"confirmations", cfg.Confirmations,
+				)
+			} else {
+				logger.Info("node rejoined the majority chain - quarantine lifted",
+					"node_name", node.Name,
+				)
+			}
+		}
+		if lb.metrics != nil {
+			value := 0.0
+			if forked {
+				value = 1
+			}
+			lb.metrics.Gauge("node.forked", value, []string{
+				fmt.Sprintf("node:%s", node.Name),
+			}, 1)
+		}
+	}
+}
+
+// compareRoots finds the root reported by a strict majority of the votes for a slot or epoch and
+// records in verdicts whether each voting node is on a minority fork. A node found forked by one
+// comparison stays forked even if another comparison agrees with it. A lone vote is not judged,
+// and without a majority the disagreement is reported but no node is judged.
+func (lb *LoadBalancer) compareRoots(kind string, at uint64, votes []rootVote, verdicts map[*beaconnode.BeaconNode]bool) {
+	counts := make(map[string]int)
+	total := 0
+	for _, vote := range votes {
+		if vote.node != nil {
+			counts[vote.root]++
+			total++
+		}
+	}
+	if total < 2 {
+		// Nothing to compare against
+		return
+	}
+
+	atKey := "slot"
+	if kind == forkKindFinalized {
+		atKey = "epoch"
+	}
+
+	if len(counts) < 2 {
+		for _, vote := range votes {
+			if vote.node != nil {
+				agree(verdicts, vote.node)
+			}
+		}
+		return
+	}
+
+	majority, majorityCount := "", 0
+	for root, count := range counts {
+		if count > majorityCount {
+			majority, majorityCount = root, count
+		}
+	}
+
+	if majorityCount*2 <= total {
+		roots := make(map[string]string, total)
+		for _, vote := range votes {
+			if vote.node != nil {
+				roots[vote.node.Name] = vote.root
+			}
+		}
+		logger.Warn("nodes disagree on root without a majority",
+			"kind", kind,
+			atKey, at,
+			"roots", roots,
+		)
+		if lb.metrics != nil {
+			lb.metrics.Incr("fork.divergence", []string{
+				fmt.Sprintf("kind:%s", kind),
+			}, 1)
+		}
+		return
+	}
+
+	for _, vote := range votes {
+		if vote.node == nil {
+			continue
+		}
+		if vote.root == majority {
+			agree(verdicts, vote.node)
+			continue
+		}
+
+		verdicts[vote.node] = true
+		logger.Warn("node disagrees with the majority root",
+			"node_name", vote.node.Name,
+			"kind", kind,
+			atKey, at,
+			"node_root", vote.root,
+			"majority_root", majority,
+			"majority_nodes", majorityCount,
+			"compared_nodes", total,
+		)
+		if lb.metrics != nil {
+			lb.metrics.Incr("fork.detected", []string{
+				fmt.Sprintf("node:%s", vote.node.Name),
+				fmt.Sprintf("kind:%s", kind),
+			}, 1)
+		}
+	}
+}
+
+// keepsHealthyNode reports whether at least one healthy node stays out of quarantine once the
+// given quarantine decisions are applied, or whether there are no healthy nodes to keep
+func (lb *LoadBalancer) keepsHealthyNode(quarantine map[*beaconnode.BeaconNode]bool) bool {
+	healthy := lb.GetHealthyNodes()
+	for _, node := range healthy {
+		forked, decided := quarantine[node]
+		if !decided {
+			forked = node.IsForked()
+		}
+		if !forked {
+			return true
+		}
+	}
+	return len(healthy) == 0
+}
+
+// agree records that a node agreed with the majority, unless another comparison found it forked
+func agree(verdicts map[*beaconnode.BeaconNode]bool, node *beaconnode.BeaconNode) {
+	if _, ok := verdicts[node]; !ok {
+		verdicts[node] = false
+	}
+}
+
+// skipForkedNodes leaves out nodes on a minority fork. If every node is quarantined, for instance
+// because the nodes that outvoted them have since become unhealthy, they are all returned rather
+// than failing every request.
+func (lb *LoadBalancer) skipForkedNodes(nodes []*beaconnode.BeaconNode) []*beaconnode.BeaconNode {
+	routable := make([]*beaconnode.BeaconNode, 0, len(nodes))
+	for _, node := range nodes {
+		if !node.IsForked() {
+			routable = append(routable, node)
+		}
+	}
+	if len(routable) == 0 {
+		return nodes
+	}
+	return routable
+}
@@ -0,0 +1,173 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+// forkView is what a beacon node reports about its chain
+type forkView struct {
+	headSlot       uint64
+	blockRoot      string
+	finalizedEpoch uint64
+	finalizedRoot  string
+}
+
+// newForkServer creates a beacon node reporting the chain in view, answering other requests with
+// its name. The slot of the latest block root requested is stored in blockSlot.
+func newForkServer(name string, view *atomic.Pointer[forkView], blockSlot *atomic.Uint64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := view.Load()
+		switch {
+		case r.URL.Path == "/eth/v1/node/syncing":
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+		case r.URL.Path == "/eth/v1/beacon/headers/head":
+			fmt.Fprintf(w, `{"data":{"root":"%s","header":{"message":{"slot":"%d"}}}}`, v.blockRoot, v.headSlot)
+		case strings.HasPrefix(r.URL.Path, "/eth/v1/beacon/blocks/"):
+			slot, _ := strconv.ParseUint(strings.Split(r.URL.Path, "/")[5], 10, 64)
+			blockSlot.Store(slot)
+			fmt.Fprintf(w, `{"data":{"root":"%s"}}`, v.blockRoot)
+		case r.URL.Path == "/eth/v1/beacon/states/head/finality_checkpoints":
+			fmt.Fprintf(w, `{"data":{"finalized":{"epoch":"%d","root":"%s"}}}`, v.finalizedEpoch, v.finalizedRoot)
+		default:
+			w.Write([]byte(name))
+		}
+	}))
+}
+
+// newForkLoadBalancer creates a load balancer with fork detection over three nodes whose chains
+// are set through the returned views
+func newForkLoadBalancer(t *testing.T) (*LoadBalancer, []*atomic.Pointer[forkView], *atomic.Uint64) {
+	t.Helper()
+	names := []string{"primary", "backup1", "backup2"}
+	views := make([]*atomic.Pointer[forkView], len(names))
+	nodes := make([]config.NodeConfig, len(names))
+	blockSlot := &atomic.Uint64{}
+	for i, name := range names {
+		views[i] = &atomic.Pointer[forkView]{}
+		server := newForkServer(name, views[i], blockSlot)
+		t.Cleanup(server.Close)
+		nodes[i] = config.NodeConfig{Name: name, URL: server.URL, Type: "lighthouse"}
+	}
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Metrics.Enabled = false
+	cfg.ForkDetection.Enabled = true
+	cfg.ForkDetection.Confirmations = 2
+	cfg.Beacons.Nodes = names
+	cfg.Beacons.SetParsedNodes(nodes)
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+	return lb, views, blockSlot
+}
+
+// setForkViews makes the nodes report views
+func setForkViews(views []*atomic.Pointer[forkView], reported [3]forkView) {
+	for i := range views {
+		views[i].Store(&reported[i])
+	}
+}
+
+func TestForkDetectionQuarantinesMinorityFork(t *testing.T) {
+	lb, views, _ := newForkLoadBalancer(t)
+
+	agreed := forkView{headSlot: 100, blockRoot: "0xaaa", finalizedEpoch: 1, finalizedRoot: "0xf1"}
+	testCases := []struct {
+		name         string
+		views        [3]forkView
+		expectedNode string
+	}{
+		{"nodes agree", [3]forkView{agreed, agreed, agreed}, "primary"},
+		{"primary on minority head fork", [3]forkView{{100, "0xbbb", 1, "0xf1"}, agreed, agreed}, "backup1"},
+		{"primary rejoins", [3]forkView{agreed, agreed, agreed}, "primary"},
+		{"primary finalized a different checkpoint", [3]forkView{{100, "0xaaa", 1, "0xf2"}, agreed, agreed}, "backup1"},
+		{"no majority keeps previous state", [3]forkView{agreed, {100, "0xbbb", 2, "0xf2"}, {100, "0xccc", 3, "0xf3"}}, "backup1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setForkViews(views, tc.views)
+			for range lb.config().ForkDetection.Confirmations {
+				lb.detectForks()
+			}
+
+			req := httptest.NewRequest("GET", "/eth/v1/node/version", nil)
+			w := httptest.NewRecorder()
+			lb.ServeHTTP(w, req)
+
+			if w.Body.String() != tc.expectedNode {
+				t.Errorf("Expected request to be served by %s, got %q", tc.expectedNode, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestForkDetectionComparesBehindTheTip(t *testing.T) {
+	lb, views, blockSlot := newForkLoadBalancer(t)
+	agreed := forkView{headSlot: 100, blockRoot: "0xaaa", finalizedEpoch: 1, finalizedRoot: "0xf1"}
+
+	setForkViews(views, [3]forkView{{101, "0xbbb", 1, "0xf1"}, agreed, agreed})
+	lb.detectForks()
+	if got, want := blockSlot.Load(), 100-lb.config().ForkDetection.Depth; got != want {
+		t.Errorf("Expected block roots compared at slot %d, got %d", want, got)
+	}
+	if lb.GetNode("primary").IsForked() {
+		t.Error("Expected a single disagreement not to quarantine the node")
+	}
+
+	// The disagreement clears before it is confirmed
+	setForkViews(views, [3]forkView{agreed, agreed, agreed})
+	lb.detectForks()
+	setForkViews(views, [3]forkView{{100, "0xbbb", 1, "0xf1"}, agreed, agreed})
+	lb.detectForks()
+	if lb.GetNode("primary").IsForked() {
+		t.Error("Expected disagreements to count only when consecutive")
+	}
+}
+
+func TestForkDetectionKeepsLastHealthyNode(t *testing.T) {
+	lb, views, _ := newForkLoadBalancer(t)
+	agreed := forkView{headSlot: 100, blockRoot: "0xaaa", finalizedEpoch: 1, finalizedRoot: "0xf1"}
+
+	// The backups outvote the primary but are unhealthy, so the primary is all there is
+	lb.mu.Lock()
+	lb.healthyNodes = lb.healthyNodes[:1]
+	lb.mu.Unlock()
+	setForkViews(views, [3]forkView{{100, "0xbbb", 1, "0xf1"}, agreed, agreed})
+	for range lb.config().ForkDetection.Confirmations {
+		lb.detectForks()
+	}
+
+	if lb.GetNode("primary").IsForked() {
+		t.Error("Expected the last healthy node not to be quarantined")
+	}
+}
+
+func TestSkipForkedNodesFallsBackWhenAllForked(t *testing.T) {
+	lb := newControlsLoadBalancer(t)
+	nodes := lb.nodes()
+	nodes[0].SetForked(true)
+	if got := lb.skipForkedNodes(nodes); len(got) != 2 {
+		t.Errorf("Expected the forked node to be skipped, got %d nodes", len(got))
+	}
+
+	for _, node := range nodes {
+		node.SetForked(true)
+	}
+	if got := lb.skipForkedNodes(nodes); len(got) != 3 {
+		t.Errorf("Expected every node to be kept once all are forked, got %d nodes", len(got))
+	}
+}
@@ -0,0 +1,138 @@
+package loadbalancer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+	"github.com/zircuit-labs/consensus-proxy/cmd/logger"
+)
+
+// StartHeadTracking starts a background goroutine that continuously fetches every node's head
+// until ctx is done, so that requests can skip nodes that have fallen behind
+func (lb *LoadBalancer) StartHeadTracking(ctx context.Context) {
+	if !lb.config().HeadTracking.Enabled {
+		return
+	}
+
+	ticker := time.NewTicker(lb.config().HeadTracking.PollInterval)
+
+	go func() {
+		defer ticker.Stop()
+		lb.updateHeads()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				lb.updateHeads()
+			}
+		}
+	}()
+
+	logger.Info("started head tracking routine",
+		"interval", lb.config().HeadTracking.PollInterval,
+		"max_slot_lag", lb.config().HeadTracking.MaxSlotLag,
+	)
+}
+
+// updateHeads fetches the head of every node concurrently and reports each node's lag
+func (lb *LoadBalancer) updateHeads() {
+	var wg sync.WaitGroup
+	for _, node := range lb.nodes() {
+		wg.Add(1)
+		go func(n *beaconnode.BeaconNode) {
+			defer wg.Done()
+
+			slot, root, err := n.FetchHead(lb.config().HealthCheck.Timeout)
+			if err != nil {
+				logger.Debug("failed to fetch node head",
+					"node_name", n.Name,
+					"error", err,
+				)
+				if lb.metrics != nil {
+					lb.metrics.Incr("head.fetch_failed", []string{
+						fmt.Sprintf("node:%s", n.Name),
+					}, 1)
+				}
+				return
+			}
+			n.SetHead(slot, root)
+		}(node)
+	}
+	wg.Wait()
+
+	best := lb.bestHeadSlot()
+	for _, node := range lb.nodes() {
+		slot, root, ok := node.GetHead()
+		if !ok {
+			continue
+		}
+		// A head fetched after best was computed may be ahead of it
+		var lag uint64
+		if slot < best {
+			lag = best - slot
+		}
+
+		if lag > lb.config().HeadTracking.MaxSlotLag {
+			logger.Debug("node head is lagging",
+				"node_name", node.Name,
+				"head_slot", slot,
+				"head_root", root,
+				"best_slot", best,
+				"lag", lag,
+			)
+		}
+
+		if lb.metrics != nil {
+			lb.metrics.Gauge("node.head_slot", float64(slot), []string{
+				fmt.Sprintf("node:%s", node.Name),
+			}, 1)
+			lb.metrics.Gauge("node.head_lag", float64(lag), []string{
+				fmt.Sprintf("node:%s", node.Name),
+			}, 1)
+		}
+	}
+}
+
+// bestHeadSlot returns the highest head slot known across all nodes
+func (lb *LoadBalancer) bestHeadSlot() uint64 {
+	var best uint64
+	for _, node := range lb.nodes() {
+		if slot, _, ok := node.GetHead(); ok && slot > best {
+			best = slot
+		}
+	}
+	return best
+}
+
+// routableNodes returns the healthy nodes that requests should be sent to, leaving out cordoned
+// nodes, nodes on a minority fork, nodes with an open circuit and nodes that are lagging behind the chain head
+func (lb *LoadBalancer) routableNodes() []*beaconnode.BeaconNode {
+	return lb.skipLaggingNodes(lb.skipOpenCircuits(lb.skipForkedNodes(lb.skipCordonedNodes(lb.GetHealthyNodes()))))
+}
+
+// skipLaggingNodes leaves out nodes whose head is more than max_slot_lag slots behind the
+// best known head. Nodes whose head is not known yet are kept. If every node is lagging,
+// they are all returned rather than failing the request outright.
+func (lb *LoadBalancer) skipLaggingNodes(nodes []*beaconnode.BeaconNode) []*beaconnode.BeaconNode {
+	if !lb.config().HeadTracking.Enabled {
+		return nodes
+	}
+
+	best := lb.bestHeadSlot()
+	routable := make([]*beaconnode.BeaconNode, 0, len(nodes))
+	for _, node := range nodes {
+		if lagging(node, best, lb.config().HeadTracking.MaxSlotLag) {
+			continue
+		}
+		routable = append(routable, node)
+	}
+
+	if len(routable) == 0 {
+		return nodes
+	}
+	return routable
+}
@@ -0,0 +1,122 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+// newHeadServer creates a beacon node reporting the given head slot, answering other requests with its name
+func newHeadServer(name string, slot *uint64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/node/syncing":
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+		case "/eth/v1/beacon/headers/head":
+			fmt.Fprintf(w, `{"data":{"root":"0x%s","header":{"message":{"slot":"%d"}}}}`, name, atomic.LoadUint64(slot))
+		default:
+			w.Write([]byte(name))
+		}
+	}))
+}
+
+func TestHeadTrackingSkipsLaggingNodes(t *testing.T) {
+	primarySlot, backupSlot := uint64(100), uint64(100)
+	primary := newHeadServer("primary", &primarySlot)
+	defer primary.Close()
+	backup := newHeadServer("backup", &backupSlot)
+	defer backup.Close()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Metrics.Enabled = false
+	cfg.HeadTracking.Enabled = true
+	cfg.HeadTracking.MaxSlotLag = 2
+	cfg.Beacons.Nodes = []string{"primary", "backup"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "primary", URL: primary.URL, Type: "lighthouse"},
+		{Name: "backup", URL: backup.URL, Type: "lighthouse"},
+	})
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+
+	testCases := []struct {
+		name         string
+		primarySlot  uint64
+		backupSlot   uint64
+		expectedNode string
+	}{
+		{"nodes in step", 100, 100, "primary"},
+		{"primary within tolerance", 100, 102, "primary"},
+		{"primary lagging", 100, 103, "backup"},
+		{"primary caught up", 103, 103, "primary"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			atomic.StoreUint64(&primarySlot, tc.primarySlot)
+			atomic.StoreUint64(&backupSlot, tc.backupSlot)
+			lb.updateHeads()
+
+			req := httptest.NewRequest("GET", "/eth/v1/node/version", nil)
+			w := httptest.NewRecorder()
+			lb.ServeHTTP(w, req)
+
+			if w.Body.String() != tc.expectedNode {
+				t.Errorf("Expected request to be served by %s, got %q", tc.expectedNode, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRoutableNodesFallsBackWhenAllLagging(t *testing.T) {
+	primarySlot, backupSlot := uint64(100), uint64(200)
+	primary := newHeadServer("primary", &primarySlot)
+	defer primary.Close()
+	backup := newHeadServer("backup", &backupSlot)
+	defer backup.Close()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Metrics.Enabled = false
+	cfg.HeadTracking.Enabled = true
+	cfg.HeadTracking.MaxSlotLag = 2
+	cfg.Beacons.Nodes = []string{"primary", "backup"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "primary", URL: primary.URL, Type: "lighthouse"},
+		{Name: "backup", URL: backup.URL, Type: "lighthouse"},
+	})
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+	lb.updateHeads()
+
+	// The best head belongs to the backup; once it is unhealthy the lagging primary is all that is left
+	lb.mu.Lock()
+	lb.healthyNodes = lb.healthyNodes[:1]
+	lb.mu.Unlock()
+
+	nodes := lb.routableNodes()
+	if len(nodes) != 1 || nodes[0].Name != "primary" {
+		t.Errorf("Expected to fall back to the lagging primary, got %d nodes", len(nodes))
+	}
+
+	// With head tracking disabled, the healthy nodes are used as they are
+	lb.config().HeadTracking.Enabled = false
+	if len(lb.routableNodes()) != 1 {
+		t.Error("Expected healthy nodes to be returned unfiltered when head tracking is disabled")
+	}
+}
@@ -1,6 +1,7 @@
 package loadbalancer
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"sync"
@@ -21,13 +22,14 @@ type healthCheckResult struct {
 // It returns an error if no nodes are healthy, or logs warnings for unhealthy nodes.
 func (lb *LoadBalancer) StartupHealthCheck() error {
 	logger.Info("performing startup health checks on all beacon nodes")
+	nodes := lb.nodes()
 
 	// Create a channel to receive results
-	resultsChan := make(chan healthCheckResult, len(lb.nodes))
+	resultsChan := make(chan healthCheckResult, len(nodes))
 	var wg sync.WaitGroup
 
 	// Launch concurrent health checks for all nodes
-	for _, node := range lb.nodes {
+	for _, node := range nodes {
 		wg.Add(1)
 		go func(n *beaconnode.BeaconNode) {
 			defer wg.Done()
@@ -37,7 +39,7 @@ func (lb *LoadBalancer) StartupHealthCheck() error {
 			)
 
 			// Use lightweight CheckSyncStatus method to avoid double logging
-			isHealthy, err := n.CheckSyncStatus(lb.config.HealthCheck)
+			isHealthy, err := n.CheckSyncStatus(lb.config().HealthCheck)
 
 			resultsChan <- healthCheckResult{
 				node:      n,
@@ -79,14 +81,14 @@ func (lb *LoadBalancer) StartupHealthCheck() error {
 
 	// Log summary
 	logger.Info("startup health check completed",
-		"total_nodes", len(lb.nodes),
+		"total_nodes", len(nodes),
 		"healthy_nodes", len(lb.healthyNodes),
 		"unhealthy_nodes", len(unhealthyNodes),
 	)
 
 	// Fail if no nodes are healthy
 	if len(lb.healthyNodes) == 0 {
-		return fmt.Errorf("startup health check failed: no healthy nodes available (total nodes: %d)", len(lb.nodes))
+		return fmt.Errorf("startup health check failed: no healthy nodes available (total nodes: %d)", len(nodes))
 	}
 
 	// Warn if some nodes are unhealthy
@@ -101,51 +103,65 @@ func (lb *LoadBalancer) StartupHealthCheck() error {
 }
 
 // StartPeriodicHealthCheck starts a background goroutine that periodically checks all nodes
-// and updates the healthyNodes slice. This ensures backup servers are monitored continuously.
-func (lb *LoadBalancer) StartPeriodicHealthCheck() {
-	ticker := time.NewTicker(lb.config.HealthCheck.Interval)
+// and updates the healthyNodes slice until ctx is done. This ensures the primary and backup servers are monitored continuously.
+func (lb *LoadBalancer) StartPeriodicHealthCheck(ctx context.Context) {
+	ticker := time.NewTicker(lb.config().HealthCheck.Interval)
 
 	go func() {
-		for range ticker.C {
-			lb.performHealthCheck()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				lb.performHealthCheck()
+			}
 		}
 	}()
 
 	logger.Info("started periodic health check routine",
-		"interval", lb.config.HealthCheck.Interval,
+		"interval", lb.config().HealthCheck.Interval,
 	)
 }
 
-// performHealthCheck checks backup nodes and updates the healthyNodes list
-// Only backup nodes are checked periodically; primary is checked on-demand when failing
+// performHealthCheck checks every node, including the current primary, and updates the healthyNodes list.
+// A primary that fails primary_failures_for_demotion consecutive checks is demoted so that a healthy
+// backup takes over, even if it never returns a server error.
 func (lb *LoadBalancer) performHealthCheck() {
-	// Get backup nodes to check
+	// A reload must not swap the nodes while their health is being collected
+	lb.reloadMu.Lock()
+	defer lb.reloadMu.Unlock()
+
+	// Get the current primary and the backup nodes to check
+	var primary *beaconnode.BeaconNode
 	backupNodes := make([]*beaconnode.BeaconNode, 0)
-	for _, node := range lb.nodes {
+	for _, node := range lb.nodes() {
 		if node.IsBackup() {
 			backupNodes = append(backupNodes, node)
+		} else if primary == nil {
+			primary = node
 		}
 	}
 
-	if len(backupNodes) == 0 {
-		logger.Debug("no backup nodes to health check")
-		return
+	nodesToCheck := backupNodes
+	if primary != nil {
+		nodesToCheck = append([]*beaconnode.BeaconNode{primary}, backupNodes...)
 	}
 
-	logger.Debug("performing periodic health check on backup nodes", "count", len(backupNodes))
+	logger.Debug("performing periodic health check", "count", len(nodesToCheck))
 
 	// Create a channel to receive results
-	resultsChan := make(chan healthCheckResult, len(backupNodes))
+	resultsChan := make(chan healthCheckResult, len(nodesToCheck))
 	var wg sync.WaitGroup
 
-	// Launch concurrent health checks for backup nodes only
-	for _, node := range backupNodes {
+	// Launch concurrent health checks for all nodes
+	for _, node := range nodesToCheck {
 		wg.Add(1)
 		go func(n *beaconnode.BeaconNode) {
 			defer wg.Done()
 
 			// Use the HealthCheck method
-			isHealthy, healthErr := n.HealthCheck(lb.config.HealthCheck)
+			isHealthy, healthErr := n.HealthCheck(lb.config().HealthCheck)
 
 			resultsChan <- healthCheckResult{
 				node:      n,
@@ -164,8 +180,14 @@ func (lb *LoadBalancer) performHealthCheck() {
 	// Collect results and update healthyNodes for backups
 	newHealthyBackups := make([]*beaconnode.BeaconNode, 0)
 	unhealthyBackupCount := 0
+	var primaryResult *healthCheckResult
 
 	for result := range resultsChan {
+		if result.node == primary {
+			primaryResult = &result
+			continue
+		}
+
 		if result.isHealthy {
 			// Increment consecutive successes for healthy nodes
 			result.node.IncrementSuccess()
@@ -182,66 +204,24 @@ func (lb *LoadBalancer) performHealthCheck() {
 			// Reset consecutive successes for unhealthy nodes
 			result.node.ResetSuccesses()
 			unhealthyBackupCount++
-
-			// Emit failure metrics with detailed reason
-			if lb.metrics != nil {
-				if healthErr, ok := result.err.(*beaconnode.HealthCheckError); ok {
-					// Handle specific health check errors
-					switch healthErr.Reason {
-					case "request_failed":
-						lb.metrics.Incr("healthcheck.failed", []string{
-							fmt.Sprintf("node:%s", result.node.Name),
-							"reason:request_failed",
-						}, 1)
-					case "non_200_status":
-						lb.metrics.Incr("healthcheck.failed", []string{
-							fmt.Sprintf("node:%s", result.node.Name),
-							"reason:non_200_status",
-							fmt.Sprintf("status_code:%d", healthErr.StatusCode),
-						}, 1)
-					case "read_body_failed":
-						lb.metrics.Incr("healthcheck.failed", []string{
-							fmt.Sprintf("node:%s", result.node.Name),
-							"reason:read_body_failed",
-						}, 1)
-					case "json_parse_failed":
-						lb.metrics.Incr("healthcheck.failed", []string{
-							fmt.Sprintf("node:%s", result.node.Name),
-							"reason:json_parse_failed",
-						}, 1)
-					case "is_syncing", "sync_distance_not_zero", "not_synced":
-						// Node is not synced
-						lb.metrics.Incr("healthcheck.not_synced", []string{
-							fmt.Sprintf("node:%s", result.node.Name),
-							fmt.Sprintf("reason:%s", healthErr.Reason),
-							fmt.Sprintf("is_syncing:%t", healthErr.IsSyncing),
-							fmt.Sprintf("sync_distance:%s", healthErr.SyncDistance),
-						}, 1)
-					default:
-						// Generic failure
-						lb.metrics.Incr("healthcheck.failed", []string{
-							fmt.Sprintf("node:%s", result.node.Name),
-							"reason:unknown",
-						}, 1)
-					}
-				} else if result.err != nil {
-					// Generic error
-					lb.metrics.Incr("healthcheck.failed", []string{
-						fmt.Sprintf("node:%s", result.node.Name),
-						"reason:unknown",
-					}, 1)
-				}
-			}
+			lb.recordHealthCheckFailure(result.node, result.err)
 		}
 	}
 
+	// Apply the primary's demotion policy before the healthy list is rebuilt
+	primaryPassed := false
+	if primaryResult != nil {
+		primaryPassed = lb.handlePrimaryHealth(*primaryResult, len(newHealthyBackups) > 0)
+	}
+
 	// Update the healthyNodes slice with thread safety
 	lb.mu.Lock()
 
-	// Check if original primary is ready for failback
+	// Check if the preferred primary (the original primary, or the node promoted by an
+	// operator) is ready for failback
 	var originalPrimary *beaconnode.BeaconNode
 	for _, node := range newHealthyBackups {
-		if node.OriginalPriority == 0 {
+		if lb.isPreferredPrimary(node) {
 			originalPrimary = node
 			break
 		}
@@ -251,19 +231,19 @@ func (lb *LoadBalancer) performHealthCheck() {
 	shouldFailback := false
 	if originalPrimary != nil {
 		consecutiveSuccesses := originalPrimary.GetConsecutiveSuccesses()
-		if consecutiveSuccesses >= int64(lb.config.HealthCheck.SuccessfulChecksForFailback) {
+		if consecutiveSuccesses >= int64(lb.config().HealthCheck.SuccessfulChecksForFailback) {
 			shouldFailback = true
 			logger.Info("original primary ready for failback",
 				"node_name", originalPrimary.Name,
 				"consecutive_successes", consecutiveSuccesses,
-				"required", lb.config.HealthCheck.SuccessfulChecksForFailback,
+				"required", lb.config().HealthCheck.SuccessfulChecksForFailback,
 			)
 		}
 	}
 
 	// Find current primary
 	var currentPrimary *beaconnode.BeaconNode
-	for _, node := range lb.nodes {
+	for _, node := range lb.nodes() {
 		if node.IsPrimary() {
 			currentPrimary = node
 			break
@@ -281,10 +261,10 @@ func (lb *LoadBalancer) performHealthCheck() {
 		)
 
 		// Demote current primary back to its original priority
-		currentPrimary.SetPriority(currentPrimary.OriginalPriority)
+		currentPrimary.SetPriority(lb.restingPriority(currentPrimary))
 		logger.Info("failback: demoting temporary primary to original priority",
 			"node_name", currentPrimary.Name,
-			"restored_priority", currentPrimary.OriginalPriority,
+			"restored_priority", currentPrimary.GetPriority(),
 		)
 
 		if lb.metrics != nil {
@@ -294,26 +274,34 @@ func (lb *LoadBalancer) performHealthCheck() {
 		}
 	}
 
-	// Restore all healthy nodes to their original priorities
+	// Restore all healthy nodes to their original priorities. A demoted preferred primary
+	// only gets its priority back through failback, after enough successful checks.
 	for _, node := range newHealthyBackups {
-		if node.GetPriority() != node.OriginalPriority {
+		if lb.isPreferredPrimary(node) {
+			continue
+		}
+		if resting := lb.restingPriority(node); node.GetPriority() != resting {
 			logger.Debug("restoring node to original priority",
 				"node_name", node.Name,
 				"current_priority", node.GetPriority(),
-				"original_priority", node.OriginalPriority,
+				"original_priority", resting,
 			)
-			node.SetPriority(node.OriginalPriority)
+			node.SetPriority(resting)
 		}
 	}
 
 	// Build updated healthy nodes list including current primary if healthy
 	updatedHealthyNodes := make([]*beaconnode.BeaconNode, 0)
 
-	// Add current primary if it's in the healthy list (from previous checks or just promoted)
+	// Add current primary if it passed its check or is in the healthy list (from previous checks or just promoted)
 	currentPrimary = nil // Re-check after potential failback
-	for _, node := range lb.nodes {
+	for _, node := range lb.nodes() {
 		if node.IsPrimary() {
 			currentPrimary = node
+			if node == primary && primaryPassed {
+				updatedHealthyNodes = append(updatedHealthyNodes, node)
+				break
+			}
 			// Check if it's in the healthy list
 			for _, healthyNode := range lb.healthyNodes {
 				if healthyNode.Name == node.Name {
@@ -327,10 +315,10 @@ func (lb *LoadBalancer) performHealthCheck() {
 
 	// If no primary exists, promote the first healthy backup
 	if currentPrimary == nil && len(newHealthyBackups) > 0 {
-		// Find the backup with OriginalPriority == 0 first (original primary)
+		// Find the preferred primary first (original primary, or the node promoted by an operator)
 		var nodeToPromote *beaconnode.BeaconNode
 		for _, node := range newHealthyBackups {
-			if node.OriginalPriority == 0 {
+			if lb.isPreferredPrimary(node) {
 				nodeToPromote = node
 				break
 			}
@@ -368,11 +356,17 @@ func (lb *LoadBalancer) performHealthCheck() {
 
 	// Add all healthy backup nodes
 	updatedHealthyNodes = append(updatedHealthyNodes, newHealthyBackups...)
+
+	// Backups were collected in the order their checks completed, so restore priority order
+	sort.SliceStable(updatedHealthyNodes, func(i, j int) bool {
+		return updatedHealthyNodes[i].GetPriority() < updatedHealthyNodes[j].GetPriority()
+	})
 	lb.healthyNodes = updatedHealthyNodes
 	lb.mu.Unlock()
 
 	// Log summary
 	logger.Debug("periodic health check completed",
+		"primary_healthy", primaryPassed,
 		"backup_nodes_checked", len(backupNodes),
 		"healthy_backups", len(newHealthyBackups),
 		"unhealthy_backups", unhealthyBackupCount,
@@ -384,3 +378,106 @@ func (lb *LoadBalancer) performHealthCheck() {
 		lb.metrics.Gauge("loadbalancer.unhealthy_backup_nodes", float64(unhealthyBackupCount), nil, 1)
 	}
 }
+
+// handlePrimaryHealth applies the primary's demotion policy to its health check result and reports
+// whether the check passed. After primary_failures_for_demotion consecutive failed checks the primary
+// is demoted to backup priority, as long as a healthy backup can take over.
+func (lb *LoadBalancer) handlePrimaryHealth(result healthCheckResult, backupAvailable bool) bool {
+	node := result.node
+	if result.isHealthy {
+		node.ResetFailedChecks()
+		if lb.metrics != nil {
+			lb.metrics.Incr("healthcheck.success", []string{
+				fmt.Sprintf("node:%s", node.Name),
+			}, 1)
+		}
+		return true
+	}
+
+	lb.recordHealthCheckFailure(node, result.err)
+	failedChecks := node.IncrementFailedChecks()
+	threshold := lb.config().HealthCheck.PrimaryFailuresForDemotion
+
+	if failedChecks < int64(threshold) || !backupAvailable {
+		logger.Warn("primary node failed health check",
+			"node_name", node.Name,
+			"consecutive_failed_checks", failedChecks,
+			"threshold", threshold,
+			"backup_available", backupAvailable,
+			"error", result.err,
+		)
+		return false
+	}
+
+	logger.Warn("primary node failover triggered by health checks - demoting to backup priority",
+		"node_name", node.Name,
+		"node_url", node.URL,
+		"consecutive_failed_checks", failedChecks,
+		"threshold", threshold,
+		"error", result.err,
+	)
+
+	// Demote to the lowest priority so a healthy backup is promoted in its place
+	node.SetPriority(len(lb.nodes()))
+	node.ResetFailedChecks()
+	node.ResetSuccesses()
+
+	if lb.metrics != nil {
+		lb.metrics.Incr("node.primary_demoted", []string{
+			fmt.Sprintf("node:%s", node.Name),
+			"reason:health_check",
+		}, 1)
+	}
+	return false
+}
+
+// recordHealthCheckFailure emits the metric for a failed health check with its detailed reason
+// and the probe that failed
+func (lb *LoadBalancer) recordHealthCheckFailure(node *beaconnode.BeaconNode, err error) {
+	if lb.metrics != nil {
+		if healthErr, ok := err.(*beaconnode.HealthCheckError); ok {
+			tags := []string{
+				fmt.Sprintf("node:%s", node.Name),
+				fmt.Sprintf("probe:%s", healthErr.Probe),
+				fmt.Sprintf("reason:%s", healthErr.Reason),
+			}
+
+			// Handle specific health check errors
+			switch healthErr.Reason {
+			case "request_failed", "read_body_failed", "json_parse_failed", "invalid_sync_distance",
+				"peer_count_low", "finality_lag_exceeded":
+				lb.metrics.Incr("healthcheck.failed", tags, 1)
+			case "non_200_status", "node_health_unavailable":
+				lb.metrics.Incr("healthcheck.failed", append(tags,
+					fmt.Sprintf("status_code:%d", healthErr.StatusCode),
+				), 1)
+			case "is_syncing", "sync_distance_exceeded", "is_optimistic", "el_offline":
+				// Node is not synced
+				lb.metrics.Incr("healthcheck.not_synced", append(tags,
+					fmt.Sprintf("is_syncing:%t", healthErr.IsSyncing),
+					fmt.Sprintf("sync_distance:%s", healthErr.SyncDistance),
+					fmt.Sprintf("is_optimistic:%t", healthErr.IsOptimistic),
+					fmt.Sprintf("el_offline:%t", healthErr.ELOffline),
+				), 1)
+			case "node_health_syncing":
+				lb.metrics.Incr("healthcheck.not_synced", append(tags,
+					fmt.Sprintf("status_code:%d", healthErr.StatusCode),
+				), 1)
+			case "head_stale":
+				lb.metrics.Incr("healthcheck.not_synced", tags, 1)
+			default:
+				// Generic failure
+				lb.metrics.Incr("healthcheck.failed", []string{
+					fmt.Sprintf("node:%s", node.Name),
+					"reason:unknown",
+				}, 1)
+			}
+		} else if err != nil {
+			// Generic error
+			lb.metrics.Incr("healthcheck.failed", []string{
+				fmt.Sprintf("node:%s", node.Name),
+				"reason:unknown",
+			}, 1)
+		}
+	}
+}
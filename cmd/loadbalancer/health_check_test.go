@@ -3,6 +3,7 @@ package loadbalancer
 import (
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -317,7 +318,7 @@ func TestStartPeriodicHealthCheck(t *testing.T) {
 	requestCount = 0
 
 	// Start periodic health check
-	lb.StartPeriodicHealthCheck()
+	lb.StartPeriodicHealthCheck(t.Context())
 
 	// Wait for at least 2 periodic checks (backup node only)
 	// Each check should happen every 100ms
@@ -340,8 +341,8 @@ func TestStartPeriodicHealthCheck(t *testing.T) {
 	}
 }
 
-// TestStartPeriodicHealthCheck_BackupOnly tests that periodic checks only check backup nodes
-func TestStartPeriodicHealthCheck_BackupOnly(t *testing.T) {
+// TestStartPeriodicHealthCheck_ChecksPrimary tests that periodic checks cover the primary as well as backups
+func TestStartPeriodicHealthCheck_ChecksPrimary(t *testing.T) {
 	primaryRequests := 0
 	backupRequests := 0
 
@@ -395,7 +396,7 @@ func TestStartPeriodicHealthCheck_BackupOnly(t *testing.T) {
 	backupRequests = 0
 
 	// Start periodic health check
-	lb.StartPeriodicHealthCheck()
+	lb.StartPeriodicHealthCheck(t.Context())
 
 	// Wait for periodic checks
 	time.Sleep(350 * time.Millisecond)
@@ -405,9 +406,9 @@ func TestStartPeriodicHealthCheck_BackupOnly(t *testing.T) {
 		t.Errorf("Expected at least 2 periodic checks for backup, got %d", backupRequests)
 	}
 
-	// Primary should not receive periodic checks (only checked on-demand when failing)
-	if primaryRequests > 0 {
-		t.Logf("Primary received %d periodic checks (expected 0, but may receive some during failover/failback)", primaryRequests)
+	// Primary is actively checked too
+	if primaryRequests < 2 {
+		t.Errorf("Expected at least 2 periodic checks for primary, got %d", primaryRequests)
 	}
 
 	t.Logf("Startup checks - Primary: %d, Backup: %d", initialPrimaryRequests, initialBackupRequests)
@@ -471,7 +472,7 @@ func TestStartPeriodicHealthCheck_NodeRecovery(t *testing.T) {
 	}
 
 	// Start periodic health check
-	lb.StartPeriodicHealthCheck()
+	lb.StartPeriodicHealthCheck(t.Context())
 
 	// Wait a bit, then make backup healthy
 	time.Sleep(150 * time.Millisecond)
@@ -486,3 +487,152 @@ func TestStartPeriodicHealthCheck_NodeRecovery(t *testing.T) {
 		t.Errorf("Expected 2 healthy nodes after recovery, got %d", len(healthyNodes))
 	}
 }
+
+// TestPeriodicHealthCheck_PrimaryDemotion tests that a primary that stalls without returning
+// server errors is demoted by health checks and restored through failback once it recovers
+func TestPeriodicHealthCheck_PrimaryDemotion(t *testing.T) {
+	var primaryOptimistic atomic.Bool
+	primaryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eth/v1/node/syncing" {
+			if primaryOptimistic.Load() {
+				w.Write([]byte(`{"data":{"is_syncing":true,"sync_distance":"3"}}`))
+				return
+			}
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer primaryServer.Close()
+
+	backupServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+	}))
+	defer backupServer.Close()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Metrics.Enabled = false
+	cfg.HealthCheck.PrimaryFailuresForDemotion = 2
+	cfg.HealthCheck.SuccessfulChecksForFailback = 2
+	cfg.Beacons.Nodes = []string{"primary", "backup"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "primary", URL: primaryServer.URL, Type: "lighthouse"},
+		{Name: "backup", URL: backupServer.URL, Type: "prysm"},
+	})
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+	primary, backup := lb.nodes()[0], lb.nodes()[1]
+
+	// A single failed check keeps the primary in place
+	primaryOptimistic.Store(true)
+	lb.performHealthCheck()
+	if !primary.IsPrimary() {
+		t.Fatal("Expected the primary to survive a single failed check")
+	}
+
+	// The second failed check demotes it and promotes the backup
+	lb.performHealthCheck()
+	if primary.IsPrimary() || !backup.IsPrimary() {
+		t.Fatalf("Expected the backup to be promoted, got priorities primary=%d backup=%d", primary.GetPriority(), backup.GetPriority())
+	}
+	healthyNodes := lb.GetHealthyNodes()
+	if len(healthyNodes) != 1 || healthyNodes[0] != backup {
+		t.Fatalf("Expected only the backup to be healthy, got %d nodes", len(healthyNodes))
+	}
+
+	// Once it recovers, the original primary waits for failback before taking over again
+	primaryOptimistic.Store(false)
+	lb.performHealthCheck()
+	if primary.IsPrimary() {
+		t.Fatal("Expected the original primary to wait for failback")
+	}
+	lb.performHealthCheck()
+	if !primary.IsPrimary() || backup.IsPrimary() {
+		t.Fatalf("Expected failback to the original primary, got priorities primary=%d backup=%d", primary.GetPriority(), backup.GetPriority())
+	}
+}
+
+// TestPeriodicHealthCheck_PrimaryKeptWithoutBackup tests that an unhealthy primary is not demoted
+// when no healthy backup could take over
+func TestPeriodicHealthCheck_PrimaryKeptWithoutBackup(t *testing.T) {
+	var primarySyncing atomic.Bool
+	primaryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if primarySyncing.Load() {
+			w.Write([]byte(`{"data":{"is_syncing":true,"sync_distance":"3"}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+	}))
+	defer primaryServer.Close()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Metrics.Enabled = false
+	cfg.Beacons.Nodes = []string{"primary"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "primary", URL: primaryServer.URL, Type: "lighthouse"},
+	})
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+
+	primarySyncing.Store(true)
+	for i := 0; i < 3; i++ {
+		lb.performHealthCheck()
+	}
+	if !lb.nodes()[0].IsPrimary() || len(lb.GetHealthyNodes()) != 1 {
+		t.Error("Expected the only node to stay primary and in rotation")
+	}
+}
+
+// TestPeriodicHealthCheck_KeepsPriorityOrder tests that backups stay in priority order when
+// their health checks complete out of order
+func TestPeriodicHealthCheck_KeepsPriorityOrder(t *testing.T) {
+	names := []string{"primary", "backup1", "backup2"}
+	nodes := make([]config.NodeConfig, len(names))
+	for i, name := range names {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// The highest priority backup answers last
+			if name == "backup1" {
+				time.Sleep(50 * time.Millisecond)
+			}
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+		}))
+		defer server.Close()
+		nodes[i] = config.NodeConfig{Name: name, URL: server.URL, Type: "lighthouse"}
+	}
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Metrics.Enabled = false
+	cfg.Beacons.Nodes = names
+	cfg.Beacons.SetParsedNodes(nodes)
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+
+	lb.performHealthCheck()
+	healthy := lb.GetHealthyNodes()
+	if len(healthy) != len(names) {
+		t.Fatalf("Expected %d healthy nodes, got %d", len(names), len(healthy))
+	}
+	for i, node := range healthy {
+		if node.Name != names[i] {
+			t.Errorf("Expected %s at position %d, got %s", names[i], i, node.Name)
+		}
+	}
+}
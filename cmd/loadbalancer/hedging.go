@@ -0,0 +1,221 @@
+package loadbalancer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+	"github.com/zircuit-labs/consensus-proxy/cmd/logger"
+)
+
+// hedgeLatencyPercentile is the node latency percentile used as the hedge delay when use_node_p95 is set
+const hedgeLatencyPercentile = 0.95
+
+// hedgeBudget caps hedged requests to a fraction of eligible requests across all clients.
+// Every eligible request earns `ratio` tokens, every hedge spends one.
+type hedgeBudget struct {
+	mu     sync.Mutex
+	tokens float64
+	ratio  float64
+	max    float64
+}
+
+// newHedgeBudget creates a budget that starts with `burst` hedges available
+func newHedgeBudget(ratio float64, burst int) *hedgeBudget {
+	return &hedgeBudget{
+		tokens: float64(burst),
+		ratio:  ratio,
+		max:    float64(burst),
+	}
+}
+
+// deposit credits the budget for an eligible request
+func (b *hedgeBudget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.max, b.tokens+b.ratio)
+}
+
+// withdraw spends one hedge, returning false if the budget is exhausted
+func (b *hedgeBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// attemptResult is the outcome of a single attempt raced against others
+type attemptResult struct {
+	node     *beaconnode.BeaconNode
+	recorder *responseRecorder
+	duration time.Duration
+	attempt  int
+	hedge    bool
+}
+
+// isHedgeableRequest reports whether the request is a latency-sensitive GET eligible for hedging
+func (lb *LoadBalancer) isHedgeableRequest(r *http.Request) bool {
+	if !lb.config().Hedging.Enabled || r.Method != http.MethodGet {
+		return false
+	}
+	return matchesEndpoint(lb.state.Load().hedging, r.URL.Path)
+}
+
+// hedgeDelay returns how long to wait for a node before hedging to the next one
+func (lb *LoadBalancer) hedgeDelay(node *beaconnode.BeaconNode) time.Duration {
+	if lb.config().Hedging.UseNodeP95 {
+		if p95, ok := node.LatencyPercentile(hedgeLatencyPercentile); ok {
+			return p95
+		}
+	}
+	return lb.config().Hedging.Delay
+}
+
+// handleHedgedRequest proxies a latency-sensitive request like handleHTTPRequest, but if the
+// node in flight has not answered within the hedge delay, the same request is also sent to the
+// next healthy node and whichever answers successfully first is used. At most one hedge is sent
+// per request, and only while the global hedge budget allows it.
+func (lb *LoadBalancer) handleHedgedRequest(w http.ResponseWriter, r *http.Request, start time.Time) {
+	body, ok := lb.readRequestBody(w, r)
+	if !ok {
+		return
+	}
+
+	// Cancelling the overall context aborts whichever attempt lost the race
+	ctx, cancel := context.WithTimeout(r.Context(), lb.config().Server.RequestTimeout)
+	defer cancel()
+	req := r.WithContext(ctx)
+
+	healthyNodes := lb.candidateNodes(r)
+	maxAttempts := min(len(healthyNodes), lb.config().Server.MaxRetries)
+	if maxAttempts == 0 {
+		if lb.refuseUnsupportedMedia(w, r) {
+			return
+		}
+		lb.handleAllNodesFailed(r, start, 0, 0)
+		http.Error(w, "All beacon nodes unavailable", http.StatusBadGateway)
+		return
+	}
+	lb.state.Load().hedges.deposit()
+
+	hedgeTimer := time.NewTimer(0)
+	hedgeTimer.Stop()
+	defer hedgeTimer.Stop()
+	hedged := false
+
+	results := make(chan attemptResult, maxAttempts)
+	launched, pending := 0, 0
+	launch := func(hedge bool) bool {
+		// Attempts share the overall timeout, so none is started once it has run out
+		remainingTimeout := lb.config().Server.RequestTimeout - time.Since(start)
+		if remainingTimeout <= 0 {
+			return false
+		}
+		node, attempt := healthyNodes[launched], launched
+		launched++
+		pending++
+
+		// The hedge delay counts from the start of the latest attempt, using its node's delay
+		hedgeTimer.Reset(lb.hedgeDelay(node))
+
+		go func() {
+			// Attempts are buffered so that only the winner is written to the client
+			recorder, duration := lb.attemptNodeRequest(nil, node, withReplayableBody(req, body), remainingTimeout, attempt)
+			results <- attemptResult{node: node, recorder: recorder, duration: duration, attempt: attempt, hedge: hedge}
+		}()
+		return true
+	}
+
+	if !launch(false) {
+		lb.hedgedRequestTimeout(w, r, start)
+		return
+	}
+
+	var lastStatusCode int
+	for pending > 0 {
+		select {
+		case result := <-results:
+			pending--
+			lastStatusCode = result.recorder.statusCode
+			lb.recordAttemptMetrics(result.node.Name, lastStatusCode, result.duration, result.attempt)
+
+			if result.recorder.isSuccess() {
+				if result.hedge && lb.metrics != nil {
+					lb.metrics.Incr("request.hedge_won", []string{
+						fmt.Sprintf("node:%s", result.node.Name),
+						keyTag(r),
+					}, 1)
+				}
+				lb.handleSuccessResponse(w, r, result.node, result.recorder, start, lastStatusCode)
+				return
+			}
+
+			lb.handleNodeError(result.node, r, lastStatusCode, result.attempt)
+
+			// Nothing left in flight, fail over to the next node straight away
+			if pending == 0 && launched < maxAttempts && !launch(false) {
+				lb.hedgedRequestTimeout(w, r, start)
+				return
+			}
+
+		case <-hedgeTimer.C:
+			if hedged || launched >= maxAttempts {
+				continue
+			}
+			hedged = true
+
+			slowNode := healthyNodes[launched-1]
+			if !lb.state.Load().hedges.withdraw() {
+				logger.Debug("hedge budget exhausted",
+					"method", r.Method,
+					"path", r.URL.Path,
+					"node", slowNode.Name,
+				)
+				if lb.metrics != nil {
+					lb.metrics.Incr("request.hedge_budget_exhausted", []string{keyTag(r)}, 1)
+				}
+				continue
+			}
+
+			logger.Debug("hedging slow request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"slow_node", slowNode.Name,
+				"hedge_node", healthyNodes[launched].Name,
+				"elapsed", time.Since(start).String(),
+			)
+			if lb.metrics != nil {
+				lb.metrics.Incr("request.hedged", []string{
+					fmt.Sprintf("from_node:%s", slowNode.Name),
+					fmt.Sprintf("node:%s", healthyNodes[launched].Name),
+					keyTag(r),
+				}, 1)
+			}
+			launch(true)
+
+		case <-ctx.Done():
+			lb.hedgedRequestTimeout(w, r, start)
+			return
+		}
+	}
+
+	lb.handleAllNodesFailed(r, start, lastStatusCode, launched)
+	http.Error(w, "All beacon nodes unavailable", http.StatusBadGateway)
+}
+
+// hedgedRequestTimeout answers a hedged request whose overall timeout ran out
+func (lb *LoadBalancer) hedgedRequestTimeout(w http.ResponseWriter, r *http.Request, start time.Time) {
+	logger.Warn("request timeout exceeded while waiting for hedged attempts",
+		"method", r.Method,
+		"path", r.URL.Path,
+		"duration", time.Since(start).String(),
+		"timeout", lb.config().Server.RequestTimeout.String(),
+	)
+	http.Error(w, "Request timeout", http.StatusGatewayTimeout)
+}
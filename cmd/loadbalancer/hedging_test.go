@@ -0,0 +1,222 @@
+package loadbalancer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+// newHedgingServer creates a beacon node that answers every request after delay
+func newHedgingServer(name string, delay time.Duration, requests *int64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eth/v1/node/syncing" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+			return
+		}
+		atomic.AddInt64(requests, 1)
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(name))
+	}))
+}
+
+func newHedgingLoadBalancer(t *testing.T, burst int, primary, backup *httptest.Server) *LoadBalancer {
+	t.Helper()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Metrics.Enabled = false
+	cfg.Server.RequestTimeout = 2 * time.Second
+	cfg.Hedging.Enabled = true
+	cfg.Hedging.Delay = 50 * time.Millisecond
+	cfg.Hedging.BudgetRatio = 0.1
+	cfg.Hedging.BudgetBurst = burst
+	cfg.Beacons.Nodes = []string{"primary", "backup"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "primary", URL: primary.URL, Type: "lighthouse"},
+		{Name: "backup", URL: backup.URL, Type: "lighthouse"},
+	})
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+	return lb
+}
+
+func TestHedgedRequestUsesFasterNode(t *testing.T) {
+	var primaryRequests, backupRequests int64
+	primary := newHedgingServer("primary", 500*time.Millisecond, &primaryRequests)
+	defer primary.Close()
+	backup := newHedgingServer("backup", 0, &backupRequests)
+	defer backup.Close()
+
+	lb := newHedgingLoadBalancer(t, 1, primary, backup)
+
+	req := httptest.NewRequest("GET", "/eth/v1/validator/attestation_data?slot=1&committee_index=0", nil)
+	w := httptest.NewRecorder()
+	start := time.Now()
+	lb.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if w.Body.String() != "backup" {
+		t.Errorf("Expected the hedged backup to answer, got %q", w.Body.String())
+	}
+	if elapsed := time.Since(start); elapsed >= 500*time.Millisecond {
+		t.Errorf("Expected the hedge to beat the slow primary, took %v", elapsed)
+	}
+	if atomic.LoadInt64(&primaryRequests) != 1 || atomic.LoadInt64(&backupRequests) != 1 {
+		t.Errorf("Expected one request to each node, got primary=%d backup=%d",
+			atomic.LoadInt64(&primaryRequests), atomic.LoadInt64(&backupRequests))
+	}
+}
+
+func TestHedgedRequestRespectsBudget(t *testing.T) {
+	var primaryRequests, backupRequests int64
+	primary := newHedgingServer("primary", 150*time.Millisecond, &primaryRequests)
+	defer primary.Close()
+	backup := newHedgingServer("backup", 0, &backupRequests)
+	defer backup.Close()
+
+	// A single hedge is available and 0.1 is earned per request, so only the first request hedges
+	lb := newHedgingLoadBalancer(t, 1, primary, backup)
+
+	expected := []string{"backup", "primary", "primary"}
+	for i, want := range expected {
+		req := httptest.NewRequest("GET", "/eth/v1/validator/attestation_data", nil)
+		w := httptest.NewRecorder()
+		lb.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Request %d: expected status 200, got %d", i, w.Code)
+		}
+		if w.Body.String() != want {
+			t.Errorf("Request %d: expected response from %s, got %q", i, want, w.Body.String())
+		}
+	}
+
+	if got := atomic.LoadInt64(&backupRequests); got != 1 {
+		t.Errorf("Expected exactly one hedge, got %d", got)
+	}
+}
+
+func TestHedgeDelayRestartsOnFailover(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eth/v1/node/syncing" {
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+			return
+		}
+		time.Sleep(30 * time.Millisecond)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	var slowRequests, fastRequests int64
+	slow := newHedgingServer("slow", time.Second, &slowRequests)
+	defer slow.Close()
+	fast := newHedgingServer("fast", 0, &fastRequests)
+	defer fast.Close()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Metrics.Enabled = false
+	cfg.Server.RequestTimeout = 2 * time.Second
+	cfg.Hedging.Enabled = true
+	cfg.Hedging.Delay = 100 * time.Millisecond
+	cfg.Hedging.BudgetBurst = 1
+	cfg.Beacons.Nodes = []string{"failing", "slow", "fast"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "failing", URL: failing.URL, Type: "lighthouse"},
+		{Name: "slow", URL: slow.URL, Type: "lighthouse"},
+		{Name: "fast", URL: fast.URL, Type: "lighthouse"},
+	})
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+
+	// The failover to the slow node starts after 30ms, so its hedge is due a full delay later
+	w := httptest.NewRecorder()
+	start := time.Now()
+	lb.ServeHTTP(w, httptest.NewRequest("GET", "/eth/v1/validator/attestation_data", nil))
+	elapsed := time.Since(start)
+
+	if w.Code != http.StatusOK || w.Body.String() != "fast" {
+		t.Fatalf("Expected the hedge to the fast node to answer, got %d %q", w.Code, w.Body.String())
+	}
+	if elapsed < 130*time.Millisecond {
+		t.Errorf("Expected the hedge delay to restart with the failover attempt, hedged after %v", elapsed)
+	}
+}
+
+func TestHedgedRequestOnlyForEligibleEndpoints(t *testing.T) {
+	var primaryRequests, backupRequests int64
+	primary := newHedgingServer("primary", 0, &primaryRequests)
+	defer primary.Close()
+	backup := newHedgingServer("backup", 0, &backupRequests)
+	defer backup.Close()
+
+	lb := newHedgingLoadBalancer(t, 1, primary, backup)
+
+	testCases := []struct {
+		method string
+		path   string
+		hedge  bool
+	}{
+		{"GET", "/eth/v1/validator/attestation_data", true},
+		{"GET", "/eth/v2/validator/aggregate_attestation", true},
+		{"GET", "/eth/v1/validator/duties/proposer/100", true},
+		{"POST", "/eth/v1/validator/duties/attester/100", false},
+		{"GET", "/eth/v1/beacon/genesis", false},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		if got := lb.isHedgeableRequest(req); got != tc.hedge {
+			t.Errorf("%s %s: expected hedge=%v, got %v", tc.method, tc.path, tc.hedge, got)
+		}
+	}
+}
+
+func TestHedgeBudget(t *testing.T) {
+	budget := newHedgeBudget(0.5, 2)
+
+	if !budget.withdraw() || !budget.withdraw() {
+		t.Fatal("Expected the initial burst to be available")
+	}
+	if budget.withdraw() {
+		t.Error("Expected the budget to be exhausted after the burst")
+	}
+
+	budget.deposit()
+	if budget.withdraw() {
+		t.Error("Expected half a token to be insufficient for a hedge")
+	}
+	budget.deposit()
+	budget.deposit()
+	if !budget.withdraw() {
+		t.Error("Expected a hedge after earning a full token")
+	}
+
+	// Deposits never exceed the burst
+	for i := 0; i < 10; i++ {
+		budget.deposit()
+	}
+	if budget.tokens != 2 {
+		t.Errorf("Expected tokens to be capped at 2, got %v", budget.tokens)
+	}
+}
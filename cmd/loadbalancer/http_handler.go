@@ -2,6 +2,7 @@ package loadbalancer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync/atomic"
@@ -16,6 +17,14 @@ import (
 func (lb *LoadBalancer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
 
+	// Authenticate clients before anything else, so that unknown clients learn nothing
+	if lb.config().Auth.Enabled {
+		var ok bool
+		if r, ok = lb.authenticate(w, r); !ok {
+			return
+		}
+	}
+
 	// Validate endpoint before processing
 	if !lb.validator.IsValidBeaconEndpoint(r.URL.Path) {
 		logger.Warn("invalid beacon endpoint attempted",
@@ -26,17 +35,45 @@ func (lb *LoadBalancer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		)
 		http.Error(w, "Invalid Beacon Chain API endpoint", http.StatusForbidden)
 		if lb.metrics != nil {
-			lb.metrics.Incr("request.invalid_endpoint", []string{"protocol:http"}, 1)
+			lb.metrics.Incr("request.invalid_endpoint", []string{"protocol:http", keyTag(r)}, 1)
 		}
 		return
 	}
 
 	// Check if this is a WebSocket upgrade request
 	if websocket.IsWebSocketUpgrade(r) {
+		if isEventStreamRequest(r) {
+			lb.handleWebSocketEvents(w, r)
+			return
+		}
 		lb.handleWebSocket(w, r)
 		return
 	}
 
+	// Server-Sent Events subscriptions are long-lived and bypass the buffered retry path
+	if isEventStreamRequest(r) {
+		lb.handleEventStream(w, r)
+		return
+	}
+
+	// Block and pool submissions go to every healthy node at once
+	if lb.isBroadcastRequest(r) {
+		lb.handleBroadcastRequest(w, r, start)
+		return
+	}
+
+	// Security-sensitive reads are only answered once enough nodes agree
+	if lb.isQuorumRequest(r) {
+		lb.handleQuorumRequest(w, r, start)
+		return
+	}
+
+	// Latency-sensitive reads race a second node if the first is slow
+	if lb.isHedgeableRequest(r) {
+		lb.handleHedgedRequest(w, r, start)
+		return
+	}
+
 	// Regular HTTP request handling
 	lb.handleHTTPRequest(w, r, start)
 }
@@ -46,15 +83,24 @@ func (lb *LoadBalancer) handleHTTPRequest(w http.ResponseWriter, r *http.Request
 	var lastStatusCode int
 
 	// Create overall request timeout context
-	overallCtx, overallCancel := context.WithTimeout(r.Context(), lb.config.Server.RequestTimeout)
+	overallCtx, overallCancel := context.WithTimeout(r.Context(), lb.config().Server.RequestTimeout)
 	defer overallCancel()
 
-	// Get healthy nodes with proper locking to avoid race conditions
-	healthyNodes := lb.GetHealthyNodes()
+	// Capture the body once so retries against backup nodes send the same payload
+	body, ok := lb.readRequestBody(w, r)
+	if !ok {
+		return
+	}
+
+	// Get healthy nodes that are not lagging behind the chain head, in routing strategy order
+	healthyNodes := lb.candidateNodes(r)
+	if len(healthyNodes) == 0 && lb.refuseUnsupportedMedia(w, r) {
+		return
+	}
 
 	// Try each node in sequence until success
 	for i, node := range healthyNodes {
-		if i >= lb.config.Server.MaxRetries {
+		if i >= lb.config().Server.MaxRetries {
 			break
 		}
 
@@ -64,14 +110,14 @@ func (lb *LoadBalancer) handleHTTPRequest(w http.ResponseWriter, r *http.Request
 		}
 
 		// Calculate remaining timeout for this attempt
-		remainingTimeout := lb.config.Server.RequestTimeout - time.Since(start)
+		remainingTimeout := lb.config().Server.RequestTimeout - time.Since(start)
 		if remainingTimeout <= 0 {
 			http.Error(w, "Request timeout", http.StatusGatewayTimeout)
 			return
 		}
 
 		// Attempt the request
-		recorder, attemptDuration := lb.attemptNodeRequest(overallCtx, node, r, remainingTimeout, i)
+		recorder, attemptDuration := lb.attemptNodeRequest(w, node, withReplayableBody(r, body), remainingTimeout, i)
 		lastStatusCode = recorder.statusCode
 
 		// Send metrics for this attempt
@@ -87,7 +133,7 @@ func (lb *LoadBalancer) handleHTTPRequest(w http.ResponseWriter, r *http.Request
 		lb.handleNodeError(node, r, lastStatusCode, i)
 
 		// If this wasn't the last attempt, continue to next node
-		if i < len(healthyNodes)-1 && i < lb.config.Server.MaxRetries-1 {
+		if i < len(healthyNodes)-1 && i < lb.config().Server.MaxRetries-1 {
 			continue
 		}
 	}
@@ -97,6 +143,40 @@ func (lb *LoadBalancer) handleHTTPRequest(w http.ResponseWriter, r *http.Request
 	http.Error(w, "All beacon nodes unavailable", http.StatusBadGateway)
 }
 
+// readRequestBody buffers the request body for replay, writing an error response and
+// returning false if the body is too large or cannot be read
+func (lb *LoadBalancer) readRequestBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := bufferRequestBody(r, lb.config().Server.MaxRequestBodySize)
+	if err == nil {
+		return body, true
+	}
+
+	var tooLarge *errRequestBodyTooLarge
+	if errors.As(err, &tooLarge) {
+		logger.Warn("request body too large",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"content_length", r.ContentLength,
+			"max_request_body_size", lb.config().Server.MaxRequestBodySize,
+			"remote_addr", r.RemoteAddr,
+		)
+		if lb.metrics != nil {
+			lb.metrics.Incr("request.body_too_large", []string{keyTag(r)}, 1)
+		}
+		http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+		return nil, false
+	}
+
+	logger.Warn("failed to read request body",
+		"method", r.Method,
+		"path", r.URL.Path,
+		"remote_addr", r.RemoteAddr,
+		"error", err,
+	)
+	http.Error(w, "Failed to read request body", http.StatusBadRequest)
+	return nil, false
+}
+
 // checkRequestTimeout checks if the overall request timeout has been exceeded
 func (lb *LoadBalancer) checkRequestTimeout(ctx context.Context, start time.Time, r *http.Request, nodeName string) bool {
 	select {
@@ -106,7 +186,7 @@ func (lb *LoadBalancer) checkRequestTimeout(ctx context.Context, start time.Time
 			"method", r.Method,
 			"path", r.URL.Path,
 			"duration", totalDuration.String(),
-			"timeout", lb.config.Server.RequestTimeout.String(),
+			"timeout", lb.config().Server.RequestTimeout.String(),
 			"node", nodeName,
 		)
 		return true
@@ -115,21 +195,68 @@ func (lb *LoadBalancer) checkRequestTimeout(ctx context.Context, start time.Time
 	}
 }
 
-// attemptNodeRequest attempts to proxy a request to a specific node
-func (lb *LoadBalancer) attemptNodeRequest(overallCtx context.Context, node *beaconnode.BeaconNode, r *http.Request, timeout time.Duration, attemptNum int) (*responseRecorder, time.Duration) {
-	ctx, cancel := context.WithTimeout(overallCtx, timeout)
+// attemptNodeRequest attempts to proxy a request to a specific node.
+// The timeout only bounds the time until the node responds: once a large successful
+// response starts streaming to w, the deadline becomes an idle timeout that is pushed
+// back whenever the node sends more of the body.
+func (lb *LoadBalancer) attemptNodeRequest(w http.ResponseWriter, node *beaconnode.BeaconNode, r *http.Request, timeout time.Duration, attemptNum int) (*responseRecorder, time.Duration) {
+	// A node whose probe slot was taken by a concurrent request is skipped without contacting it
+	send, admitted := lb.acquireCircuit(node)
+	if !send {
+		logger.Debug("circuit breaker refused request - trying the next node",
+			"node_name", node.Name,
+			"method", r.Method,
+			"path", r.URL.Path,
+			"attempt", attemptNum+1,
+		)
+		return newResponseRecorder(nil, 0, nil), 0
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
 	defer cancel()
+	timer := time.AfterFunc(timeout, cancel)
+	defer timer.Stop()
 
 	reqWithTimeout := r.WithContext(ctx)
-	recorder := &responseRecorder{}
+	idleTimeout := lb.config().Proxy.StreamIdleTimeout
+	recorder := newResponseRecorder(w, lb.config().Proxy.StreamingThreshold, func() { timer.Reset(idleTimeout) })
 
 	node.IncrementRequests()
+	node.BeginRequest()
 	attemptStart := time.Now()
 
 	// Try the request
 	node.Proxy.ServeHTTP(recorder, reqWithTimeout)
+	duration := time.Since(attemptStart)
+	node.EndRequest()
+
+	failed := recorder.statusCode >= HTTPStatusServerErrorMin || recorder.statusCode == 0
+
+	// Streamed responses are left out since their duration includes the body transfer
+	if !recorder.committed {
+		if recorder.isSuccess() {
+			node.RecordLatency(duration)
+		}
+		lb.state.Load().strategy.Observe(node, duration, failed)
+	}
+
+	// Requests sent past an unavailable circuit have nothing to report to the breaker
+	if !admitted {
+		return recorder, duration
+	}
+
+	// Requests cancelled by the client, or by a hedge that already has its answer, say nothing about the node
+	if r.Context().Err() == nil {
+		breakerDuration := duration
+		if recorder.committed {
+			breakerDuration = 0
+		}
+		node.Breaker.Record(breakerDuration, failed)
+	} else {
+		node.Breaker.Abandon()
+	}
 
-	return recorder, time.Since(attemptStart)
+	return recorder, duration
 }
 
 // recordAttemptMetrics sends metrics for a request attempt
@@ -159,9 +286,11 @@ func (lb *LoadBalancer) handleSuccessResponse(w http.ResponseWriter, r *http.Req
 			fmt.Sprintf("node:%s", node.Name),
 			fmt.Sprintf("status_code:%d", statusCode),
 			"result:success",
+			keyTag(r),
 		}, 1)
 		lb.metrics.Incr("request.success", []string{
 			fmt.Sprintf("node:%s", node.Name),
+			keyTag(r),
 		}, 1)
 	}
 }
@@ -173,19 +302,19 @@ func (lb *LoadBalancer) handleNodeError(node *beaconnode.BeaconNode, r *http.Req
 		consecutiveErrors := atomic.LoadInt64(&node.ConsecutiveErrors)
 
 		// Check if this is the primary node and if we've reached threshold
-		if node.IsPrimary() && consecutiveErrors >= int64(lb.config.Failover.ErrorThreshold) {
+		if node.IsPrimary() && consecutiveErrors >= int64(lb.config().Failover.ErrorThreshold) {
 			logger.Warn("primary node failover triggered - demoting to backup priority",
 				"node_name", node.Name,
 				"node_url", node.URL,
 				"consecutive_errors", consecutiveErrors,
-				"threshold", lb.config.Failover.ErrorThreshold,
+				"threshold", lb.config().Failover.ErrorThreshold,
 				"status_code", statusCode,
 				"attempt", attemptNum+1,
 			)
 
 			// Demote primary to backup priority (use high priority number to put it at the end)
 			// This ensures it will be healthchecked periodically along with other backups
-			maxPriority := len(lb.nodes)
+			maxPriority := len(lb.nodes())
 			node.SetPriority(maxPriority)
 			logger.Info("primary node demoted to backup",
 				"node_name", node.Name,
@@ -206,6 +335,7 @@ func (lb *LoadBalancer) handleNodeError(node *beaconnode.BeaconNode, r *http.Req
 			if lb.metrics != nil {
 				lb.metrics.Incr("node.primary_demoted", []string{
 					fmt.Sprintf("node:%s", node.Name),
+					"reason:server_errors",
 				}, 1)
 			}
 		}
@@ -218,6 +348,7 @@ func (lb *LoadBalancer) handleNodeError(node *beaconnode.BeaconNode, r *http.Req
 		lb.metrics.Incr("request.failover", []string{
 			fmt.Sprintf("from_node:%s", node.Name),
 			fmt.Sprintf("status_code:%d", statusCode),
+			keyTag(r),
 		}, 1)
 	}
 }
@@ -260,7 +391,7 @@ func (lb *LoadBalancer) handleAllNodesFailed(r *http.Request, start time.Time, l
 		"total_duration", totalDuration.String(),
 		"last_status_code", lastStatusCode,
 		"attempts", attempts,
-		"max_retries", lb.config.Server.MaxRetries,
+		"max_retries", lb.config().Server.MaxRetries,
 	)
 
 	if lb.metrics != nil {
@@ -268,7 +399,8 @@ func (lb *LoadBalancer) handleAllNodesFailed(r *http.Request, start time.Time, l
 			"node:all",
 			fmt.Sprintf("status_code:%d", lastStatusCode),
 			"result:failure",
+			keyTag(r),
 		}, 1)
-		lb.metrics.Incr("request.failure", nil, 1)
+		lb.metrics.Incr("request.failure", []string{keyTag(r)}, 1)
 	}
 }
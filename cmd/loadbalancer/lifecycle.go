@@ -0,0 +1,78 @@
+package loadbalancer
+
+import (
+	"context"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/logger"
+)
+
+// streamPollInterval is how often Shutdown checks whether client streams have finished
+const streamPollInterval = 100 * time.Millisecond
+
+// Run keeps the nodes' state current with periodic health checks, head tracking and fork
+// detection until ctx is done
+func (lb *LoadBalancer) Run(ctx context.Context) {
+	lb.StartPeriodicHealthCheck(ctx)
+	lb.StartHeadTracking(ctx)
+	lb.StartForkDetection(ctx)
+	<-ctx.Done()
+}
+
+// Ready reports whether the load balancer should receive traffic. It stops being ready as
+// soon as shutdown begins, and whenever too few nodes can take traffic.
+func (lb *LoadBalancer) Ready() bool {
+	return lb.Readiness().Ready
+}
+
+// BeginShutdown marks the load balancer as not ready, so that load balancers in front of the
+// proxy stop sending it traffic, while requests keep being served
+func (lb *LoadBalancer) BeginShutdown() {
+	if lb.shuttingDown.CompareAndSwap(false, true) {
+		logger.Info("shutdown started - reporting not ready")
+	}
+}
+
+// Shutdown ends every client stream and waits for them to finish, or until ctx is done.
+// Proxied websockets are sent a going away close frame, and event streams are closed by
+// the event hub. New streams are refused from then on. It returns ctx's error if streams
+// were still open when ctx was done.
+func (lb *LoadBalancer) Shutdown(ctx context.Context) error {
+	lb.BeginShutdown()
+	lb.closeOnce.Do(func() {
+		close(lb.closing)
+	})
+	lb.events.Close()
+
+	ticker := time.NewTicker(streamPollInterval)
+	defer ticker.Stop()
+	for lb.streams.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			logger.Warn("shutdown grace period ended with client streams still open",
+				"streams", lb.streams.Load(),
+			)
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	logger.Info("all client streams closed")
+	return nil
+}
+
+// beginStream registers a client websocket or event stream, returning false once shutdown
+// has begun and no new streams are accepted
+func (lb *LoadBalancer) beginStream() bool {
+	lb.streams.Add(1)
+	if lb.shuttingDown.Load() {
+		lb.streams.Add(-1)
+		return false
+	}
+	return true
+}
+
+// endStream unregisters a client stream
+func (lb *LoadBalancer) endStream() {
+	lb.streams.Add(-1)
+}
@@ -0,0 +1,129 @@
+package loadbalancer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+// newStreamingServer creates a synced beacon node that echoes websocket messages and emits
+// head events every 10ms
+func newStreamingServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	events := newEventStreamServer(t, 10*time.Millisecond)
+	t.Cleanup(events.Close)
+
+	var upgrader websocket.Upgrader
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !websocket.IsWebSocketUpgrade(r) {
+			events.Config.Handler.ServeHTTP(w, r)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			conn.WriteMessage(messageType, data)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestShutdown(t *testing.T) {
+	upstream := newStreamingServer(t)
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Metrics.Enabled = false
+	cfg.Beacons.Nodes = []string{"primary"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "primary", URL: upstream.URL, Type: "lighthouse"},
+	})
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+	if !lb.Ready() {
+		t.Fatal("Expected the load balancer to be ready before shutdown")
+	}
+
+	proxy := httptest.NewServer(lb)
+	defer proxy.Close()
+
+	wsURL := strings.Replace(proxy.URL, "http://", "ws://", 1) + "/eth/v1/node/version"
+	wsConn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("Websocket dial failed: %v", err)
+	}
+	defer wsConn.Close()
+	if err := wsConn.WriteMessage(websocket.TextMessage, []byte("ping")); err != nil {
+		t.Fatalf("Websocket write failed: %v", err)
+	}
+	if _, data, err := wsConn.ReadMessage(); err != nil || string(data) != "ping" {
+		t.Fatalf("Expected the websocket to be proxied, got %q (err: %v)", data, err)
+	}
+
+	req, _ := http.NewRequest("GET", proxy.URL+"/eth/v1/events?topics=head", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Event stream request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := lb.Shutdown(ctx); err != nil {
+		t.Fatalf("Expected streams to close within the grace period: %v", err)
+	}
+	if lb.Ready() {
+		t.Error("Expected the load balancer not to be ready after shutdown")
+	}
+
+	// The websocket client gets a going away close frame
+	_, _, err = wsConn.ReadMessage()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != websocket.CloseGoingAway {
+		t.Errorf("Expected a going away close frame, got %v", err)
+	}
+
+	// The event stream ends
+	done := make(chan struct{})
+	go func() {
+		io.Copy(io.Discard, resp.Body)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Error("Expected the event stream to end")
+	}
+
+	// New streams are refused
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Event stream request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected new streams to be refused with 503, got %d", resp.StatusCode)
+	}
+}
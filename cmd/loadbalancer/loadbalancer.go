@@ -3,10 +3,13 @@ package loadbalancer
 import (
 	"fmt"
 	"net/http"
+	"regexp"
 	"sync"
+	"sync/atomic"
 
 	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
 	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+	"github.com/zircuit-labs/consensus-proxy/cmd/events"
 	"github.com/zircuit-labs/consensus-proxy/cmd/logger"
 	"github.com/zircuit-labs/consensus-proxy/cmd/metrics"
 	"github.com/zircuit-labs/consensus-proxy/cmd/validator"
@@ -16,13 +19,34 @@ import (
 
 // LoadBalancer manages multiple beacon nodes and handles load balancing
 type LoadBalancer struct {
-	nodes        []*beaconnode.BeaconNode
+	state         atomic.Pointer[snapshot] // replaced as a whole on reload
+	metrics       metrics.Client
+	upgrader      websocket.Upgrader
+	validator     *validator.BeaconEndpointValidator
+	events        *events.Hub
+	manualPrimary atomic.Pointer[beaconnode.BeaconNode] // node promoted by an operator, nil without an override
+	reloadMu      sync.Mutex                            // serializes reloads with periodic health checks
+	shuttingDown  atomic.Bool                           // set once shutdown begins
+	closing       chan struct{}                         // closed by Shutdown to end client streams
+	closeOnce     sync.Once
+	streams       atomic.Int64 // client websockets and event streams currently open
+	mu            sync.RWMutex
+	healthyNodes  []*beaconnode.BeaconNode
+}
+
+// snapshot holds the nodes and the routing settings built from one configuration. A reload
+// builds a new snapshot and swaps it in at once, so a request sees either the old or the new one.
+type snapshot struct {
 	config       *config.Config
-	metrics      metrics.Client
-	upgrader     websocket.Upgrader
-	validator    *validator.BeaconEndpointValidator
-	mu           sync.RWMutex
-	healthyNodes []*beaconnode.BeaconNode
+	nodes        []*beaconnode.BeaconNode
+	broadcast    []*regexp.Regexp
+	hedging      []*regexp.Regexp
+	hedges       *hedgeBudget
+	quorum       []*regexp.Regexp
+	strategy     Strategy
+	routes       []*route
+	capabilities map[string]*capabilities // keyed by beacon client type
+	apiKeys      []*apiKey
 }
 
 // New creates a new LoadBalancer instance
@@ -55,8 +79,7 @@ func New(cfg *config.Config) (*LoadBalancer, error) {
 	}
 
 	lb := &LoadBalancer{
-		nodes:     nodes,
-		config:    cfg,
+		closing:   make(chan struct{}),
 		validator: validator.NewBeaconEndpointValidator(),
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
@@ -74,12 +97,109 @@ func New(cfg *config.Config) (*LoadBalancer, error) {
 		return nil, fmt.Errorf("failed to initialize metrics client: %v", err)
 	}
 
+	for _, node := range nodes {
+		lb.watchCircuitBreaker(node)
+	}
+
+	state, err := newSnapshot(cfg, nodes, nil)
+	if err != nil {
+		return nil, err
+	}
+	lb.state.Store(state)
+
+	// Event subscriptions are shared across clients and follow the routable node order
+	lb.events = events.NewHub(lb.routableNodes, cfg.Events, cfg.Server.MaxRetries, lb.metrics)
+
 	return lb, nil
 }
 
+// newSnapshot builds the routing settings of cfg for nodes. previous is the snapshot being
+// replaced, or nil, and carries over the rate limit state of unchanged API keys.
+func newSnapshot(cfg *config.Config, nodes []*beaconnode.BeaconNode, previous *snapshot) (*snapshot, error) {
+	state := &snapshot{
+		config: cfg,
+		nodes:  nodes,
+	}
+
+	var err error
+	state.strategy, err = newStrategy(cfg.Routing.Strategy, cfg.Routing)
+	if err != nil {
+		return nil, err
+	}
+	state.routes, err = newRoutes(cfg, nodes)
+	if err != nil {
+		return nil, err
+	}
+	state.capabilities, err = newCapabilities(cfg.Capabilities)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Auth.Enabled {
+		var previousKeys []*apiKey
+		if previous != nil {
+			previousKeys = previous.apiKeys
+		}
+		if state.apiKeys, err = newAPIKeys(cfg.Auth, previousKeys); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Broadcast.Enabled {
+		if state.broadcast, err = compileEndpoints("broadcast", cfg.Broadcast.Endpoints); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Hedging.Enabled {
+		if state.hedging, err = compileEndpoints("hedging", cfg.Hedging.Endpoints); err != nil {
+			return nil, err
+		}
+		state.hedges = newHedgeBudget(cfg.Hedging.BudgetRatio, cfg.Hedging.BudgetBurst)
+	}
+
+	if cfg.Quorum.Enabled {
+		if state.quorum, err = compileEndpoints("quorum", cfg.Quorum.Endpoints); err != nil {
+			return nil, err
+		}
+	}
+
+	return state, nil
+}
+
+// config returns the configuration currently in effect
+func (lb *LoadBalancer) config() *config.Config {
+	return lb.state.Load().config
+}
+
+// nodes returns the currently configured nodes in configuration order
+func (lb *LoadBalancer) nodes() []*beaconnode.BeaconNode {
+	return lb.state.Load().nodes
+}
+
+// GetConfig returns the configuration currently in effect, including any reloads
+func (lb *LoadBalancer) GetConfig() *config.Config {
+	return lb.config()
+}
+
 // GetNodes returns all configured nodes (for health/status endpoints)
 func (lb *LoadBalancer) GetNodes() []*beaconnode.BeaconNode {
-	return lb.nodes
+	return lb.nodes()
+}
+
+// GetPrimary returns the node currently serving as primary, or nil if there is none
+func (lb *LoadBalancer) GetPrimary() *beaconnode.BeaconNode {
+	for _, node := range lb.nodes() {
+		if node.IsPrimary() {
+			return node
+		}
+	}
+	return nil
+}
+
+// StrategyName returns the name of the routing strategy used outside of routes
+func (lb *LoadBalancer) StrategyName() string {
+	return lb.state.Load().strategy.Name()
 }
 
 // GetHealthyNodes returns a slice of currently healthy nodes
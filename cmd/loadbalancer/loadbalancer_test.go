@@ -1,6 +1,7 @@
 package loadbalancer
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -616,3 +617,330 @@ func TestLoadBalancer_GetHealthyNodes(t *testing.T) {
 		t.Error("Expected node2 to be in healthy nodes list")
 	}
 }
+
+func TestLoadBalancerStreamsLargeResponse(t *testing.T) {
+	chunk := strings.Repeat("a", 4096)
+	const chunks = 16
+
+	// Server streams a large body slowly enough to outlive the request timeout
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eth/v1/node/syncing" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.WriteHeader(http.StatusOK)
+		for i := 0; i < chunks; i++ {
+			w.Write([]byte(chunk))
+			w.(http.Flusher).Flush()
+			time.Sleep(10 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Server.RequestTimeout = 50 * time.Millisecond
+	cfg.Proxy.StreamingThreshold = 1024
+	cfg.Metrics.Enabled = false
+	cfg.Beacons.Nodes = []string{"primary"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "primary", URL: server.URL, Type: "lighthouse"},
+	})
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/eth/v2/debug/beacon/states/head", nil)
+	w := httptest.NewRecorder()
+	lb.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if w.Body.Len() != len(chunk)*chunks {
+		t.Errorf("Expected %d body bytes, got %d", len(chunk)*chunks, w.Body.Len())
+	}
+	if !w.Flushed {
+		t.Error("Expected streamed response to be flushed to the client")
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/octet-stream" {
+		t.Errorf("Expected upstream Content-Type to be preserved, got %q", got)
+	}
+}
+
+func TestLoadBalancerCutsOffStalledStream(t *testing.T) {
+	chunk := strings.Repeat("a", 4096)
+
+	// Server starts streaming a large body and then stops sending
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eth/v1/node/syncing" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(chunk))
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Server.RequestTimeout = 100 * time.Millisecond
+	cfg.Proxy.StreamingThreshold = 1024
+	cfg.Proxy.StreamIdleTimeout = 50 * time.Millisecond
+	cfg.Metrics.Enabled = false
+	cfg.Beacons.Nodes = []string{"primary"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "primary", URL: server.URL, Type: "lighthouse"},
+	})
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/eth/v2/debug/beacon/states/head", nil)
+	w := httptest.NewRecorder()
+	start := time.Now()
+	lb.ServeHTTP(w, req)
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the stalled stream to be cut off after the idle timeout, took %v", elapsed)
+	}
+	if w.Code != http.StatusOK || w.Body.Len() != len(chunk) {
+		t.Errorf("Expected the streamed prefix with status 200, got %d with %d bytes", w.Code, w.Body.Len())
+	}
+}
+
+func TestLoadBalancerBuffersSmallChunkedResponse(t *testing.T) {
+	// Server answers without a Content-Length, so the reverse proxy flushes after every write
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eth/v1/node/syncing" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		for i := 0; i < 4; i++ {
+			w.Write([]byte("chunk"))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Server.RequestTimeout = 100 * time.Millisecond
+	cfg.Proxy.StreamingThreshold = 1024
+	cfg.Metrics.Enabled = false
+	cfg.Beacons.Nodes = []string{"primary"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "primary", URL: server.URL, Type: "lighthouse"},
+	})
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/eth/v1/node/version", nil)
+	w := httptest.NewRecorder()
+	lb.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || w.Body.String() != strings.Repeat("chunk", 4) {
+		t.Fatalf("Expected the full body with status 200, got %d %q", w.Code, w.Body.String())
+	}
+	if w.Flushed {
+		t.Error("Expected a response below the streaming threshold to be buffered, not streamed")
+	}
+}
+
+func TestLoadBalancerLargeErrorResponseFailsOver(t *testing.T) {
+	// Primary answers with a large error body that must not be streamed to the client
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eth/v1/node/syncing" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(strings.Repeat("e", 8192)))
+	}))
+	defer primary.Close()
+
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eth/v1/node/syncing" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(strings.Repeat("b", 8192)))
+	}))
+	defer backup.Close()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Server.RequestTimeout = 100 * time.Millisecond
+	cfg.Proxy.StreamingThreshold = 1024
+	cfg.Metrics.Enabled = false
+	cfg.Beacons.Nodes = []string{"primary", "backup"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "primary", URL: primary.URL, Type: "lighthouse"},
+		{Name: "backup", URL: backup.URL, Type: "lighthouse"},
+	})
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/eth/v2/debug/beacon/states/head", nil)
+	w := httptest.NewRecorder()
+	lb.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 from backup, got %d", w.Code)
+	}
+	if w.Body.String() != strings.Repeat("b", 8192) {
+		t.Errorf("Expected only the backup body, got %d bytes starting with %q", w.Body.Len(), w.Body.String()[:min(16, w.Body.Len())])
+	}
+}
+
+func TestLoadBalancerPostFailoverReplaysBody(t *testing.T) {
+	const payload = `[{"aggregation_bits":"0x01","data":{"slot":"1"},"signature":"0xab"}]`
+	var primaryBody, backupBody string
+
+	// Primary consumes the body and then fails
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eth/v1/node/syncing" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		primaryBody = string(b)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer primary.Close()
+
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eth/v1/node/syncing" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		backupBody = string(b)
+		if r.ContentLength != int64(len(b)) {
+			t.Errorf("Backup received Content-Length %d for %d byte body", r.ContentLength, len(b))
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backup.Close()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Server.RequestTimeout = 100 * time.Millisecond
+	cfg.Metrics.Enabled = false
+	cfg.Beacons.Nodes = []string{"primary", "backup"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "primary", URL: primary.URL, Type: "lighthouse"},
+		{Name: "backup", URL: backup.URL, Type: "lighthouse"},
+	})
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/eth/v1/beacon/pool/attestations", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	lb.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 from backup, got %d", w.Code)
+	}
+	if primaryBody != payload {
+		t.Errorf("Expected primary to receive the payload, got %q", primaryBody)
+	}
+	if backupBody != payload {
+		t.Errorf("Expected backup to receive the replayed payload, got %q", backupBody)
+	}
+}
+
+func TestLoadBalancerRejectsOversizeBody(t *testing.T) {
+	upstreamRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eth/v1/node/syncing" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+			return
+		}
+		upstreamRequests++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Server.MaxRequestBodySize = 16
+	cfg.Metrics.Enabled = false
+	cfg.Beacons.Nodes = []string{"primary"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "primary", URL: server.URL, Type: "lighthouse"},
+	})
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+
+	testCases := []struct {
+		name          string
+		contentLength int64
+	}{
+		{"declared content length", 32},
+		{"chunked body", -1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/eth/v1/beacon/pool/attestations", strings.NewReader(strings.Repeat("x", 32)))
+			req.ContentLength = tc.contentLength
+			w := httptest.NewRecorder()
+			lb.ServeHTTP(w, req)
+
+			if w.Code != http.StatusRequestEntityTooLarge {
+				t.Errorf("Expected status 413, got %d", w.Code)
+			}
+		})
+	}
+
+	if upstreamRequests != 0 {
+		t.Errorf("Expected oversize bodies to never reach a node, got %d upstream requests", upstreamRequests)
+	}
+}
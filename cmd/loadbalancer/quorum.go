@@ -0,0 +1,256 @@
+package loadbalancer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+	"github.com/zircuit-labs/consensus-proxy/cmd/logger"
+)
+
+// quorumVote is a single node's answer to a quorum read
+type quorumVote struct {
+	node     *beaconnode.BeaconNode
+	recorder *responseRecorder
+	key      string // canonical `data`, empty if the response cannot be compared
+}
+
+// quorumVoteSummary describes a node's answer in the divergence error returned to clients
+type quorumVoteSummary struct {
+	Node       string `json:"node"`
+	StatusCode int    `json:"status_code"`
+	DataHash   string `json:"data_hash,omitempty"`
+}
+
+// quorumErrorResponse is returned to the client when the nodes did not reach quorum
+type quorumErrorResponse struct {
+	Code      int                 `json:"code"`
+	Message   string              `json:"message"`
+	Quorum    int                 `json:"quorum"`
+	Responses []quorumVoteSummary `json:"responses"`
+}
+
+// isQuorumRequest reports whether the request is a security-sensitive read that must agree across nodes
+func (lb *LoadBalancer) isQuorumRequest(r *http.Request) bool {
+	if !lb.config().Quorum.Enabled || r.Method != http.MethodGet {
+		return false
+	}
+	return matchesEndpoint(lb.state.Load().quorum, r.URL.Path)
+}
+
+// canonicalData extracts the `data` field of a Beacon API response in a canonical form,
+// so that responses differing only in key order or whitespace compare equal
+func canonicalData(body []byte) (string, error) {
+	var response struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", err
+	}
+	if len(response.Data) == 0 {
+		return "", errors.New("response has no data field")
+	}
+
+	// Numbers are kept verbatim rather than round-tripped through float64
+	decoder := json.NewDecoder(bytes.NewReader(response.Data))
+	decoder.UseNumber()
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return "", err
+	}
+	canonical, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return string(canonical), nil
+}
+
+// dataHash returns a short fingerprint of canonical data for logs and error responses
+func dataHash(key string) string {
+	if key == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// handleQuorumRequest sends the request to several healthy nodes in parallel and only returns
+// the response once `quorum` of them agree on the canonical `data`. If that becomes impossible,
+// the divergence is logged and the client receives an error listing each node's answer.
+func (lb *LoadBalancer) handleQuorumRequest(w http.ResponseWriter, r *http.Request, start time.Time) {
+	body, ok := lb.readRequestBody(w, r)
+	if !ok {
+		return
+	}
+
+	quorum := lb.config().Quorum.Quorum
+	healthyNodes := lb.candidateNodes(r)
+	nodes := healthyNodes[:min(len(healthyNodes), lb.config().Quorum.Nodes)]
+	if len(healthyNodes) == 0 && lb.refuseUnsupportedMedia(w, r) {
+		return
+	}
+	if len(nodes) < quorum {
+		logger.Warn("not enough healthy nodes for quorum read",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"healthy_nodes", len(healthyNodes),
+			"quorum", quorum,
+		)
+		lb.recordQuorumOutcome(start, "insufficient_nodes")
+		http.Error(w, "Not enough healthy beacon nodes for quorum", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Outstanding requests are abandoned once the outcome is decided
+	ctx, cancel := context.WithTimeout(r.Context(), lb.config().Quorum.Timeout)
+	defer cancel()
+
+	results := make(chan quorumVote, len(nodes))
+	for _, node := range nodes {
+		go func(node *beaconnode.BeaconNode) {
+			recorder, _ := lb.attemptNodeRequest(nil, node, withReplayableBody(r.WithContext(ctx), body), lb.config().Quorum.Timeout, 0)
+			results <- quorumVote{node: node, recorder: recorder}
+		}(node)
+	}
+
+	votes := make([]quorumVote, 0, len(nodes))
+	counts := make(map[string]int)
+	largest := 0
+	for received := 0; received < len(nodes); received++ {
+		vote := <-results
+		lb.countQuorumVote(r, &vote)
+		votes = append(votes, vote)
+
+		if vote.key != "" {
+			counts[vote.key]++
+			if counts[vote.key] >= quorum {
+				lb.handleQuorumAgreed(w, r, start, vote, len(votes))
+				return
+			}
+			largest = max(largest, counts[vote.key])
+		}
+
+		// Stop waiting once no answer can reach quorum with the nodes still outstanding
+		if largest+len(nodes)-received-1 < quorum {
+			break
+		}
+	}
+
+	lb.handleQuorumFailed(w, r, start, votes)
+}
+
+// countQuorumVote canonicalizes a node's answer and updates its error counters
+func (lb *LoadBalancer) countQuorumVote(r *http.Request, vote *quorumVote) {
+	statusCode := vote.recorder.statusCode
+	if !vote.recorder.isSuccess() {
+		if statusCode >= HTTPStatusServerErrorMin || statusCode == 0 {
+			vote.node.IncrementError()
+		}
+		return
+	}
+	vote.node.ResetErrors()
+
+	key, err := canonicalData(vote.recorder.body)
+	if err != nil {
+		logger.Warn("quorum response could not be compared",
+			"node_name", vote.node.Name,
+			"method", r.Method,
+			"path", r.URL.Path,
+			"error", err,
+		)
+		return
+	}
+	vote.key = key
+}
+
+// handleQuorumAgreed returns the agreed response to the client
+func (lb *LoadBalancer) handleQuorumAgreed(w http.ResponseWriter, r *http.Request, start time.Time, vote quorumVote, received int) {
+	vote.recorder.copyToResponseWriter(w)
+
+	totalDuration := time.Since(start)
+	logger.Debug("quorum read agreed",
+		"method", r.Method,
+		"path", r.URL.Path,
+		"duration", totalDuration.String(),
+		"node_used", vote.node.Name,
+		"responses", received,
+		"quorum", lb.config().Quorum.Quorum,
+		"data_hash", dataHash(vote.key),
+	)
+	lb.recordQuorumOutcome(start, "agreed")
+}
+
+// handleQuorumFailed logs the divergence and returns an error listing each node's answer
+func (lb *LoadBalancer) handleQuorumFailed(w http.ResponseWriter, r *http.Request, start time.Time, votes []quorumVote) {
+	response := quorumErrorResponse{
+		Code:      http.StatusBadGateway,
+		Quorum:    lb.config().Quorum.Quorum,
+		Responses: make([]quorumVoteSummary, 0, len(votes)),
+	}
+
+	hashes := make(map[string]string, len(votes))
+	comparable := 0
+	for _, vote := range votes {
+		hash := dataHash(vote.key)
+		if vote.key != "" {
+			comparable++
+			hashes[vote.node.Name] = hash
+		}
+		response.Responses = append(response.Responses, quorumVoteSummary{
+			Node:       vote.node.Name,
+			StatusCode: vote.recorder.statusCode,
+			DataHash:   hash,
+		})
+	}
+
+	// Nodes answering differently is a divergence; too few answers is an availability problem
+	result := "insufficient_responses"
+	response.Message = "not enough beacon nodes answered to reach quorum"
+	if comparable >= lb.config().Quorum.Quorum {
+		result = "diverged"
+		response.Message = "beacon nodes disagree on the response"
+
+		logger.Error("beacon nodes diverged on quorum read",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"quorum", lb.config().Quorum.Quorum,
+			"data_hashes", hashes,
+		)
+		if lb.metrics != nil {
+			lb.metrics.Incr("quorum.divergence", nil, 1)
+		}
+	} else {
+		logger.Warn("quorum read failed",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"quorum", lb.config().Quorum.Quorum,
+			"comparable_responses", comparable,
+			"responses", len(votes),
+		)
+	}
+	lb.recordQuorumOutcome(start, result)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.Code)
+	json.NewEncoder(w).Encode(response)
+}
+
+// recordQuorumOutcome records metrics for a finished quorum read
+func (lb *LoadBalancer) recordQuorumOutcome(start time.Time, result string) {
+	if lb.metrics == nil {
+		return
+	}
+	lb.metrics.Incr("quorum.result", []string{
+		fmt.Sprintf("result:%s", result),
+	}, 1)
+	lb.metrics.Timing("quorum.duration", time.Since(start), []string{
+		fmt.Sprintf("result:%s", result),
+	}, 1)
+}
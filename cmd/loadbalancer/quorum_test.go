@@ -0,0 +1,204 @@
+package loadbalancer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+// newQuorumServer creates a beacon node that answers every request with the given status and body
+func newQuorumServer(status int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eth/v1/node/syncing" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+}
+
+func newQuorumLoadBalancer(t *testing.T, servers ...*httptest.Server) *LoadBalancer {
+	t.Helper()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Metrics.Enabled = false
+	cfg.Quorum.Enabled = true
+	cfg.Quorum.Nodes = 3
+	cfg.Quorum.Quorum = 2
+	cfg.Quorum.Timeout = 2 * time.Second
+
+	names := []string{"primary", "backup1", "backup2"}
+	nodes := make([]config.NodeConfig, 0, len(servers))
+	for i, server := range servers {
+		nodes = append(nodes, config.NodeConfig{Name: names[i], URL: server.URL, Type: "lighthouse"})
+	}
+	cfg.Beacons.Nodes = names[:len(servers)]
+	cfg.Beacons.SetParsedNodes(nodes)
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+	return lb
+}
+
+const finalizedA = `{"execution_optimistic":false,"data":{"finalized":{"epoch":"10","root":"0xaa"},"current_justified":{"epoch":"11","root":"0xbb"}}}`
+
+// finalizedAReordered is finalizedA with different key order and whitespace
+const finalizedAReordered = `{"data": {"current_justified": {"root": "0xbb", "epoch": "11"}, "finalized": {"root": "0xaa", "epoch": "10"}}, "execution_optimistic": false}`
+
+const finalizedB = `{"execution_optimistic":false,"data":{"finalized":{"epoch":"10","root":"0xcc"},"current_justified":{"epoch":"11","root":"0xbb"}}}`
+
+func TestQuorumRead(t *testing.T) {
+	testCases := []struct {
+		name         string
+		servers      []*httptest.Server
+		expectedCode int
+		divergence   bool
+	}{
+		{
+			name: "majority agrees",
+			servers: []*httptest.Server{
+				newQuorumServer(http.StatusOK, finalizedB),
+				newQuorumServer(http.StatusOK, finalizedA),
+				newQuorumServer(http.StatusOK, finalizedAReordered),
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "agreement despite a failed node",
+			servers: []*httptest.Server{
+				newQuorumServer(http.StatusInternalServerError, `{"code":500,"message":"internal error"}`),
+				newQuorumServer(http.StatusOK, finalizedA),
+				newQuorumServer(http.StatusOK, finalizedA),
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "nodes diverge",
+			servers: []*httptest.Server{
+				newQuorumServer(http.StatusOK, finalizedA),
+				newQuorumServer(http.StatusOK, finalizedB),
+				newQuorumServer(http.StatusServiceUnavailable, `{"code":503,"message":"syncing"}`),
+			},
+			expectedCode: http.StatusBadGateway,
+			divergence:   true,
+		},
+		{
+			name: "too few answers",
+			servers: []*httptest.Server{
+				newQuorumServer(http.StatusOK, finalizedA),
+				newQuorumServer(http.StatusOK, `not json`),
+				newQuorumServer(http.StatusNotFound, `{"code":404,"message":"state not found"}`),
+			},
+			expectedCode: http.StatusBadGateway,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, server := range tc.servers {
+				defer server.Close()
+			}
+			lb := newQuorumLoadBalancer(t, tc.servers...)
+
+			req := httptest.NewRequest("GET", "/eth/v1/beacon/states/head/finality_checkpoints", nil)
+			w := httptest.NewRecorder()
+			lb.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedCode {
+				t.Fatalf("Expected status %d, got %d: %s", tc.expectedCode, w.Code, w.Body.String())
+			}
+			if w.Code == http.StatusOK {
+				key, err := canonicalData(w.Body.Bytes())
+				if err != nil {
+					t.Fatalf("Failed to parse response: %v", err)
+				}
+				if expected, _ := canonicalData([]byte(finalizedA)); key != expected {
+					t.Errorf("Expected the agreed data, got %s", w.Body.String())
+				}
+				return
+			}
+
+			var response quorumErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode quorum error: %v", err)
+			}
+			// Collection stops early once quorum is out of reach, so not every node may be listed
+			if len(response.Responses) == 0 || len(response.Responses) > len(tc.servers) {
+				t.Errorf("Expected between 1 and %d node responses, got %d", len(tc.servers), len(response.Responses))
+			}
+			agreeing := make(map[string]int)
+			for _, vote := range response.Responses {
+				if vote.DataHash != "" {
+					agreeing[vote.DataHash]++
+					if agreeing[vote.DataHash] >= response.Quorum {
+						t.Errorf("Expected fewer than %d nodes to agree, got %d with hash %s", response.Quorum, agreeing[vote.DataHash], vote.DataHash)
+					}
+				}
+			}
+			if diverged := response.Message == "beacon nodes disagree on the response"; diverged != tc.divergence {
+				t.Errorf("Expected divergence=%v, got message %q", tc.divergence, response.Message)
+			}
+		})
+	}
+}
+
+func TestQuorumReadNeedsEnoughNodes(t *testing.T) {
+	server := newQuorumServer(http.StatusOK, finalizedA)
+	defer server.Close()
+
+	lb := newQuorumLoadBalancer(t, server)
+
+	req := httptest.NewRequest("GET", "/eth/v1/beacon/genesis", nil)
+	w := httptest.NewRecorder()
+	lb.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 with a single node, got %d", w.Code)
+	}
+
+	// Endpoints outside the quorum list keep using regular failover
+	req = httptest.NewRequest("GET", "/eth/v1/node/version", nil)
+	w = httptest.NewRecorder()
+	lb.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for a regular read, got %d", w.Code)
+	}
+}
+
+func TestCanonicalData(t *testing.T) {
+	a, err := canonicalData([]byte(`{"data":{"b":1,"a":[1,2,{"y":"1","x":"2"}]}}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	b, err := canonicalData([]byte(`{"data": {"a": [1, 2, {"x": "2", "y": "1"}], "b": 1}, "meta": "ignored"}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if a != b {
+		t.Errorf("Expected equal canonical data, got %s and %s", a, b)
+	}
+
+	// Large integers must not lose precision
+	c, _ := canonicalData([]byte(`{"data":{"balance":18446744073709551615}}`))
+	d, _ := canonicalData([]byte(`{"data":{"balance":18446744073709551614}}`))
+	if c == d {
+		t.Error("Expected large integers to be compared exactly")
+	}
+
+	if _, err := canonicalData([]byte(`{"code":404}`)); err == nil {
+		t.Error("Expected an error for a response without data")
+	}
+}
@@ -0,0 +1,83 @@
+package loadbalancer
+
+import (
+	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+)
+
+// Reasons a node that passed its health checks takes no traffic
+const (
+	UnavailableUnhealthy   = "unhealthy"
+	UnavailableCordoned    = "cordoned"
+	UnavailableForked      = "forked"
+	UnavailableCircuitOpen = "circuit_open"
+	UnavailableLagging     = "lagging"
+)
+
+// Readiness describes whether the proxy can serve traffic and which nodes it can route to
+type Readiness struct {
+	Ready           bool
+	ShuttingDown    bool
+	AvailableNodes  int // nodes that can take traffic right now
+	MinHealthyNodes int // available nodes required to be ready
+	Nodes           []NodeAvailability
+}
+
+// NodeAvailability describes whether a single node can take traffic
+type NodeAvailability struct {
+	Name      string
+	Healthy   bool
+	Available bool
+	Reason    string // why the node takes no traffic, empty if it is available
+}
+
+// Readiness reports whether at least min_healthy_nodes nodes are healthy and can take traffic:
+// they are not cordoned, on a minority fork, behind an open circuit or lagging the best head.
+// The proxy is never ready once shutdown has begun.
+func (lb *LoadBalancer) Readiness() Readiness {
+	healthy := make(map[*beaconnode.BeaconNode]bool)
+	for _, node := range lb.GetHealthyNodes() {
+		healthy[node] = true
+	}
+
+	cfg := lb.config()
+	var best uint64
+	if cfg.HeadTracking.Enabled {
+		best = lb.bestHeadSlot()
+	}
+
+	nodes := lb.nodes()
+	readiness := Readiness{
+		ShuttingDown:    lb.shuttingDown.Load(),
+		MinHealthyNodes: cfg.HealthCheck.MinHealthyNodes,
+		Nodes:           make([]NodeAvailability, 0, len(nodes)),
+	}
+	for _, node := range nodes {
+		availability := NodeAvailability{Name: node.Name, Healthy: healthy[node]}
+		switch {
+		case !availability.Healthy:
+			availability.Reason = UnavailableUnhealthy
+		case node.IsCordoned():
+			availability.Reason = UnavailableCordoned
+		case node.IsForked():
+			availability.Reason = UnavailableForked
+		case node.Breaker.State() == beaconnode.CircuitOpen:
+			availability.Reason = UnavailableCircuitOpen
+		case cfg.HeadTracking.Enabled && lagging(node, best, cfg.HeadTracking.MaxSlotLag):
+			availability.Reason = UnavailableLagging
+		default:
+			availability.Available = true
+			readiness.AvailableNodes++
+		}
+		readiness.Nodes = append(readiness.Nodes, availability)
+	}
+
+	readiness.Ready = !readiness.ShuttingDown && readiness.AvailableNodes >= readiness.MinHealthyNodes
+	return readiness
+}
+
+// lagging reports whether a node's known head is more than maxSlotLag slots behind best. A
+// head fetched after best was computed may be ahead of it, and is never lagging.
+func lagging(node *beaconnode.BeaconNode, best, maxSlotLag uint64) bool {
+	slot, _, ok := node.GetHead()
+	return ok && slot < best && best-slot > maxSlotLag
+}
@@ -0,0 +1,69 @@
+package loadbalancer
+
+import (
+	"testing"
+)
+
+func TestReadiness(t *testing.T) {
+	lb := newControlsLoadBalancer(t)
+	lb.config().HealthCheck.MinHealthyNodes = 2
+
+	readiness := lb.Readiness()
+	if !readiness.Ready || readiness.AvailableNodes != 3 {
+		t.Fatalf("Expected ready with 3 available nodes, got ready=%v available=%d", readiness.Ready, readiness.AvailableNodes)
+	}
+
+	// A cordoned node and a node that failed its health check take no traffic
+	lb.Cordon("backup1")
+	lb.mu.Lock()
+	lb.healthyNodes = lb.healthyNodes[:2]
+	lb.mu.Unlock()
+
+	readiness = lb.Readiness()
+	if readiness.Ready || readiness.AvailableNodes != 1 {
+		t.Fatalf("Expected not ready with 1 available node, got ready=%v available=%d", readiness.Ready, readiness.AvailableNodes)
+	}
+	want := []NodeAvailability{
+		{Name: "primary", Healthy: true, Available: true},
+		{Name: "backup1", Healthy: true, Reason: UnavailableCordoned},
+		{Name: "backup2", Reason: UnavailableUnhealthy},
+	}
+	for i, node := range readiness.Nodes {
+		if node != want[i] {
+			t.Errorf("Expected %+v, got %+v", want[i], node)
+		}
+	}
+
+	lb.Uncordon("backup1")
+	if !lb.Ready() {
+		t.Fatal("Expected ready once enough nodes are available again")
+	}
+
+	lb.BeginShutdown()
+	if readiness := lb.Readiness(); readiness.Ready || !readiness.ShuttingDown {
+		t.Error("Expected not ready once shutdown has begun")
+	}
+}
+
+func TestLagging(t *testing.T) {
+	node := newControlsLoadBalancer(t).nodes()[0]
+	if lagging(node, 100, 2) {
+		t.Error("Expected a node without a known head not to be lagging")
+	}
+
+	testCases := []struct {
+		slot, best uint64
+		expected   bool
+	}{
+		{100, 100, false},
+		{98, 100, false},
+		{97, 100, true},
+		{101, 100, false}, // head fetched after best was computed
+	}
+	for _, tc := range testCases {
+		node.SetHead(tc.slot, "0x01")
+		if got := lagging(node, tc.best, 2); got != tc.expected {
+			t.Errorf("Expected lagging(slot=%d, best=%d)=%v, got %v", tc.slot, tc.best, tc.expected, got)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+	"github.com/zircuit-labs/consensus-proxy/cmd/logger"
+)
+
+// retirePollInterval is how often a retired node is checked for having drained
+const retirePollInterval = time.Second
+
+// Reload applies a new configuration without dropping connections. Nodes whose configuration,
+// position and node settings are unchanged are kept along with their health, priority and
+// statistics. New and changed nodes are created and health checked before they take traffic,
+// while removed and replaced nodes are retired: they get no new requests, and are closed once
+// their in-flight requests, websockets and event streams have finished. If the configuration
+// cannot be applied, the current one stays in effect.
+func (lb *LoadBalancer) Reload(cfg *config.Config) error {
+	err := lb.reload(cfg)
+	if err != nil {
+		logger.Error("config reload rejected - keeping the current configuration",
+			"error", err,
+		)
+	}
+	if lb.metrics != nil {
+		result := "success"
+		if err != nil {
+			result = "failure"
+		}
+		lb.metrics.Incr("config.reload", []string{
+			fmt.Sprintf("result:%s", result),
+		}, 1)
+	}
+	return err
+}
+
+func (lb *LoadBalancer) reload(cfg *config.Config) error {
+	lb.reloadMu.Lock()
+	defer lb.reloadMu.Unlock()
+
+	allNodes := cfg.GetAllNodes()
+	if len(allNodes) == 0 {
+		return fmt.Errorf("at least one beacon node is required")
+	}
+
+	old := lb.state.Load()
+	oldConfigs := make(map[string]config.NodeConfig, len(old.nodes))
+	for _, nodeConfig := range old.config.GetAllNodes() {
+		oldConfigs[nodeConfig.Name] = nodeConfig
+	}
+	oldNodes := make(map[string]*beaconnode.BeaconNode, len(old.nodes))
+	for _, node := range old.nodes {
+		oldNodes[node.Name] = node
+	}
+	rebuild := beaconnode.SettingsChanged(old.config, cfg)
+
+	nodes := make([]*beaconnode.BeaconNode, 0, len(allNodes))
+	kept := make(map[*beaconnode.BeaconNode]bool, len(allNodes))
+	added := make([]*beaconnode.BeaconNode, 0)
+	for i, nodeConfig := range allNodes {
+		previous, exists := oldNodes[nodeConfig.Name]
+		if exists && !rebuild && previous.OriginalPriority == i && reflect.DeepEqual(oldConfigs[nodeConfig.Name], nodeConfig) {
+			nodes = append(nodes, previous)
+			kept[previous] = true
+			continue
+		}
+
+		node, err := beaconnode.NewBeaconNode(nodeConfig, cfg)
+		if err != nil {
+			return fmt.Errorf("failed to create beacon node %s: %v", nodeConfig.Name, err)
+		}
+		node.SetPriority(i)
+		node.OriginalPriority = i
+		// An operator's cordon carries over to the node's replacement
+		if exists && previous.IsCordoned() {
+			node.Cordon(previous.IsDraining())
+		}
+		nodes = append(nodes, node)
+		added = append(added, node)
+	}
+
+	state, err := newSnapshot(cfg, nodes, lb.state.Load())
+	if err != nil {
+		return err
+	}
+
+	// New nodes only take traffic once they pass a health check
+	passed := lb.checkNewNodes(added, cfg.HealthCheck)
+	for _, node := range added {
+		lb.watchCircuitBreaker(node)
+	}
+
+	lb.mu.Lock()
+
+	// A new node in the primary's position waits for failback if a kept node is serving as primary
+	for node := range kept {
+		if node.IsPrimary() {
+			for _, addedNode := range added {
+				if addedNode.IsPrimary() {
+					addedNode.SetPriority(len(nodes))
+				}
+			}
+			break
+		}
+	}
+
+	healthyNodes := make([]*beaconnode.BeaconNode, 0, len(nodes))
+	for _, node := range lb.healthyNodes {
+		if kept[node] {
+			healthyNodes = append(healthyNodes, node)
+		}
+	}
+	for _, node := range added {
+		if passed[node] {
+			healthyNodes = append(healthyNodes, node)
+		}
+	}
+	slices.SortStableFunc(healthyNodes, func(a, b *beaconnode.BeaconNode) int {
+		return a.GetPriority() - b.GetPriority()
+	})
+
+	// A manually promoted node that was replaced stays preferred through its replacement
+	if manual := lb.manualPrimary.Load(); manual != nil && !kept[manual] {
+		var replacement *beaconnode.BeaconNode
+		for _, node := range added {
+			if node.Name == manual.Name {
+				replacement = node
+			}
+		}
+		lb.manualPrimary.Store(replacement)
+		if replacement == nil {
+			logger.Warn("manually promoted node was removed - primary override cleared",
+				"node_name", manual.Name,
+			)
+		}
+	}
+
+	lb.state.Store(state)
+	lb.healthyNodes = healthyNodes
+	lb.mu.Unlock()
+
+	retired := make([]string, 0)
+	for _, node := range old.nodes {
+		if !kept[node] {
+			retired = append(retired, node.Name)
+			node.Cordon(true)
+			go lb.retire(node)
+		}
+	}
+
+	addedNames := make([]string, 0, len(added))
+	for _, node := range added {
+		addedNames = append(addedNames, node.Name)
+	}
+	logger.Info("config reloaded",
+		"total_nodes", len(nodes),
+		"healthy_nodes", len(healthyNodes),
+		"added_nodes", addedNames,
+		"retired_nodes", retired,
+	)
+	if settings := config.RestartRequired(old.config, cfg); len(settings) > 0 {
+		logger.Warn("some changed settings only take effect after a restart",
+			"settings", settings,
+		)
+	}
+	return nil
+}
+
+// checkNewNodes health checks nodes concurrently and returns those that passed
+func (lb *LoadBalancer) checkNewNodes(nodes []*beaconnode.BeaconNode, cfg config.HealthCheckConfig) map[*beaconnode.BeaconNode]bool {
+	passed := make(map[*beaconnode.BeaconNode]bool, len(nodes))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, node := range nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			healthy, err := node.CheckSyncStatus(cfg)
+			if !healthy {
+				logger.Warn("new node is not healthy or not synced",
+					"node_name", node.Name,
+					"error", err,
+				)
+				return
+			}
+			mu.Lock()
+			passed[node] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return passed
+}
+
+// retire waits for a node that is no longer configured to finish its in-flight requests and
+// streams, then closes its idle upstream connections
+func (lb *LoadBalancer) retire(node *beaconnode.BeaconNode) {
+	ticker := time.NewTicker(retirePollInterval)
+	defer ticker.Stop()
+	for !node.IsDrained() {
+		<-ticker.C
+	}
+
+	node.CloseIdleConnections()
+	logger.Info("retired node drained and closed",
+		"node_name", node.Name,
+	)
+}
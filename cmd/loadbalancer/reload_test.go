@@ -0,0 +1,172 @@
+package loadbalancer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+// newNamedServer creates a synced beacon node that answers non health check requests with name
+func newNamedServer(t *testing.T, name string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eth/v1/node/syncing" {
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+			return
+		}
+		w.Write([]byte(name))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// reloadConfig creates a configuration for nodes, in priority order
+func reloadConfig(nodes ...config.NodeConfig) *config.Config {
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Metrics.Enabled = false
+	cfg.Beacons.Nodes = make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		cfg.Beacons.Nodes = append(cfg.Beacons.Nodes, node.Name)
+	}
+	cfg.Beacons.SetParsedNodes(nodes)
+	return cfg
+}
+
+func TestReload(t *testing.T) {
+	primaryServer := newNamedServer(t, "primary")
+	backupServer := newNamedServer(t, "backup")
+	movedServer := newNamedServer(t, "backup-moved")
+	addedServer := newNamedServer(t, "added")
+
+	primaryConfig := config.NodeConfig{Name: "primary", URL: primaryServer.URL, Type: "lighthouse"}
+	backupConfig := config.NodeConfig{Name: "backup", URL: backupServer.URL, Type: "prysm"}
+
+	lb, err := New(reloadConfig(primaryConfig, backupConfig))
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+	primary, backup := lb.nodes()[0], lb.nodes()[1]
+	lb.Cordon("backup")
+
+	// A stream open on the backup keeps it from being closed once it is replaced
+	backup.BeginStream()
+	defer backup.EndStream()
+
+	backupConfig.URL = movedServer.URL
+	addedConfig := config.NodeConfig{Name: "added", URL: addedServer.URL, Type: "teku"}
+	if err := lb.Reload(reloadConfig(primaryConfig, backupConfig, addedConfig)); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+
+	nodes := lb.nodes()
+	if len(nodes) != 3 {
+		t.Fatalf("Expected 3 nodes after reload, got %d", len(nodes))
+	}
+	if nodes[0] != primary {
+		t.Error("Expected the unchanged primary to be kept")
+	}
+	if nodes[1] == backup || nodes[1].URL != movedServer.URL {
+		t.Error("Expected the backup with a new URL to be replaced")
+	}
+	if !nodes[1].IsCordoned() {
+		t.Error("Expected the replacement to keep the operator's cordon")
+	}
+	if !backup.IsDraining() || backup.IsDrained() {
+		t.Error("Expected the replaced backup to drain while its stream is open")
+	}
+	if nodes[2].Name != "added" || nodes[2].GetPriority() != 2 {
+		t.Errorf("Expected the added node at priority 2, got %s at %d", nodes[2].Name, nodes[2].GetPriority())
+	}
+
+	healthy := lb.GetHealthyNodes()
+	if len(healthy) != 3 || healthy[0] != primary || slices.Contains(healthy, backup) {
+		t.Errorf("Expected the kept primary and both new nodes to be healthy, got %d nodes", len(healthy))
+	}
+
+	lb.Cordon("primary")
+	if got := servedBy(lb); got != "added" {
+		t.Errorf("Expected the added node to serve while the others are cordoned, got %q", got)
+	}
+}
+
+func TestReload_Rejected(t *testing.T) {
+	server := newNamedServer(t, "primary")
+	nodeConfig := config.NodeConfig{Name: "primary", URL: server.URL, Type: "lighthouse"}
+
+	lb, err := New(reloadConfig(nodeConfig))
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+	before := lb.state.Load()
+
+	invalidStrategy := reloadConfig(nodeConfig)
+	invalidStrategy.Routing.Strategy = "random"
+
+	testCases := []struct {
+		name string
+		cfg  *config.Config
+	}{
+		{"no nodes", reloadConfig()},
+		{"invalid routing strategy", invalidStrategy},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := lb.Reload(tc.cfg); err == nil {
+				t.Fatal("Expected the reload to be rejected")
+			}
+			if lb.state.Load() != before {
+				t.Error("Expected the running configuration to stay in effect")
+			}
+			if got := servedBy(lb); got != "primary" {
+				t.Errorf("Expected requests to keep being served, got %q", got)
+			}
+		})
+	}
+}
+
+func TestReload_NodeSettings(t *testing.T) {
+	server := newNamedServer(t, "primary")
+	nodeConfig := config.NodeConfig{Name: "primary", URL: server.URL, Type: "lighthouse"}
+
+	lb, err := New(reloadConfig(nodeConfig))
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+	node := lb.nodes()[0]
+
+	// Settings read on every request are applied without recreating the node
+	cfg := reloadConfig(nodeConfig)
+	cfg.Server.MaxRetries = 7
+	if err := lb.Reload(cfg); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if lb.nodes()[0] != node || lb.config().Server.MaxRetries != 7 {
+		t.Error("Expected the node to be kept and the new settings applied")
+	}
+
+	// Proxy settings are captured by the node's transport, so it is recreated
+	cfg = reloadConfig(nodeConfig)
+	cfg.Proxy.MaxConnsPerHost++
+	if err := lb.Reload(cfg); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if lb.nodes()[0] == node || !lb.nodes()[0].IsPrimary() {
+		t.Error("Expected the node to be recreated as primary for new proxy settings")
+	}
+	if got := servedBy(lb); got != "primary" {
+		t.Errorf("Expected the recreated node to serve, got %q", got)
+	}
+}
@@ -0,0 +1,58 @@
+package loadbalancer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// errRequestBodyTooLarge is returned when a request body exceeds the configured maximum
+type errRequestBodyTooLarge struct {
+	limit int64
+}
+
+func (e *errRequestBodyTooLarge) Error() string {
+	return fmt.Sprintf("request body exceeds maximum size of %d bytes", e.limit)
+}
+
+// bufferRequestBody reads the request body once so it can be replayed on every failover attempt.
+// Returns nil for requests without a body, and errRequestBodyTooLarge if the body exceeds maxSize.
+func bufferRequestBody(r *http.Request, maxSize int64) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	defer r.Body.Close()
+
+	// Reject early when the client announces an oversize body
+	if r.ContentLength > maxSize {
+		return nil, &errRequestBodyTooLarge{limit: maxSize}
+	}
+
+	// Read one byte past the limit to detect oversize bodies without a Content-Length
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if int64(len(body)) > maxSize {
+		return nil, &errRequestBodyTooLarge{limit: maxSize}
+	}
+
+	return body, nil
+}
+
+// withReplayableBody returns a shallow copy of r whose body replays the buffered bytes.
+// Each call yields a fresh reader, so the same body can be sent to several nodes.
+func withReplayableBody(r *http.Request, body []byte) *http.Request {
+	if body == nil {
+		return r
+	}
+
+	replay := r.Clone(r.Context())
+	replay.Body = io.NopCloser(bytes.NewReader(body))
+	replay.ContentLength = int64(len(body))
+	replay.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return replay
+}
@@ -1,12 +1,32 @@
 package loadbalancer
 
-import "net/http"
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
 
-// responseRecorder captures the status code and response body for our failover logic
+// responseRecorder captures the status code and response body for our failover logic.
+// Successful responses are buffered up to a threshold; anything larger is committed to
+// the client and streamed through with flushing so big downloads never sit in memory.
 type responseRecorder struct {
 	statusCode int
 	header     http.Header
 	body       []byte
+
+	w         http.ResponseWriter // client writer, only touched once the response is committed
+	threshold int                 // maximum number of body bytes buffered before streaming
+	committed bool                // true once headers have been sent to the client
+	onStream  func()              // called when the response is committed and after each chunk streamed to w
+}
+
+// newResponseRecorder creates a recorder that streams successful responses larger than threshold to w
+func newResponseRecorder(w http.ResponseWriter, threshold int, onStream func()) *responseRecorder {
+	return &responseRecorder{
+		w:         w,
+		threshold: threshold,
+		onStream:  onStream,
+	}
 }
 
 // Header implements http.ResponseWriter
@@ -19,22 +39,119 @@ func (rr *responseRecorder) Header() http.Header {
 
 // WriteHeader implements http.ResponseWriter
 func (rr *responseRecorder) WriteHeader(code int) {
-	if rr.statusCode == 0 {
-		rr.statusCode = code
+	if rr.statusCode != 0 {
+		return
+	}
+	rr.statusCode = code
+
+	// Decide from the headers alone whether the body is worth buffering
+	if rr.isSuccess() && rr.shouldStreamFromHeaders() {
+		rr.commit()
 	}
 }
 
 // Write implements http.ResponseWriter
 func (rr *responseRecorder) Write(data []byte) (int, error) {
 	if rr.statusCode == 0 {
-		rr.statusCode = 200
+		rr.WriteHeader(http.StatusOK)
+	}
+
+	if rr.committed {
+		return rr.stream(data)
 	}
+
+	// Failed responses are discarded on failover, so only keep a bounded prefix
+	if !rr.isSuccess() {
+		if room := rr.threshold - len(rr.body); room > 0 {
+			rr.body = append(rr.body, data[:min(room, len(data))]...)
+		}
+		return len(data), nil
+	}
+
+	// Switch to streaming once the buffered body would exceed the threshold
+	if rr.w != nil && len(rr.body)+len(data) > rr.threshold {
+		rr.commit()
+		return rr.stream(data)
+	}
+
 	rr.body = append(rr.body, data...)
 	return len(data), nil
 }
 
+// Flush implements http.Flusher so the reverse proxy can push streaming responses through.
+// The reverse proxy flushes after every write when there is no Content-Length, so a flush
+// never commits by itself: responses are committed by the threshold or event stream checks.
+func (rr *responseRecorder) Flush() {
+	if rr.committed {
+		rr.flush()
+	}
+}
+
+// isSuccess reports whether the recorded status code is a success (2xx/3xx)
+func (rr *responseRecorder) isSuccess() bool {
+	return rr.statusCode >= HTTPStatusSuccessMin && rr.statusCode < HTTPStatusSuccessMax
+}
+
+// shouldStreamFromHeaders reports whether the response headers already tell us the body
+// is too large to buffer or is an open-ended stream
+func (rr *responseRecorder) shouldStreamFromHeaders() bool {
+	if rr.w == nil {
+		return false
+	}
+	if strings.HasPrefix(rr.header.Get("Content-Type"), "text/event-stream") {
+		return true
+	}
+	size, err := strconv.ParseInt(rr.header.Get("Content-Length"), 10, 64)
+	return err == nil && size > int64(rr.threshold)
+}
+
+// commit sends the recorded headers, status code and buffered body to the client
+func (rr *responseRecorder) commit() {
+	if rr.committed || rr.w == nil {
+		return
+	}
+	rr.committed = true
+	rr.progress()
+
+	for k, v := range rr.header {
+		rr.w.Header()[k] = v
+	}
+	rr.w.WriteHeader(rr.statusCode)
+	if len(rr.body) > 0 {
+		rr.w.Write(rr.body)
+		rr.body = nil
+	}
+	rr.flush()
+}
+
+// stream writes a chunk of a committed response through to the client
+func (rr *responseRecorder) stream(data []byte) (int, error) {
+	n, err := rr.w.Write(data)
+	rr.flush()
+	rr.progress()
+	return n, err
+}
+
+// progress reports that the streamed response is still moving
+func (rr *responseRecorder) progress() {
+	if rr.onStream != nil {
+		rr.onStream()
+	}
+}
+
+// flush pushes any data written so far to the client if the writer supports it
+func (rr *responseRecorder) flush() {
+	if f, ok := rr.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 // copyToResponseWriter copies the recorded response to the actual ResponseWriter
 func (rr *responseRecorder) copyToResponseWriter(w http.ResponseWriter) {
+	// Streamed responses have already been written
+	if rr.committed {
+		return
+	}
 	// Copy headers
 	for k, v := range rr.header {
 		w.Header()[k] = v
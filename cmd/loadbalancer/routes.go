@@ -0,0 +1,111 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+// route sends requests matching its paths to a dedicated pool of nodes
+type route struct {
+	name     string
+	paths    []*regexp.Regexp
+	nodes    []*beaconnode.BeaconNode // pool members in the route's priority order
+	strategy Strategy
+}
+
+// newRoutes builds the configured routes, resolving node names to the load balancer's nodes
+func newRoutes(cfg *config.Config, nodes []*beaconnode.BeaconNode) ([]*route, error) {
+	byName := make(map[string]*beaconnode.BeaconNode, len(nodes))
+	for _, node := range nodes {
+		byName[node.Name] = node
+	}
+
+	routes := make([]*route, 0, len(cfg.Routes))
+	for _, routeConfig := range cfg.Routes {
+		paths, err := compileEndpoints("route "+routeConfig.Name, routeConfig.Paths)
+		if err != nil {
+			return nil, err
+		}
+
+		strategyName := routeConfig.Strategy
+		if strategyName == "" {
+			strategyName = cfg.Routing.Strategy
+		}
+		strategy, err := newStrategy(strategyName, cfg.Routing)
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %v", routeConfig.Name, err)
+		}
+
+		pool := make([]*beaconnode.BeaconNode, 0, len(routeConfig.Nodes))
+		for _, name := range routeConfig.Nodes {
+			node, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("route %s: unknown beacon node: %s", routeConfig.Name, name)
+			}
+			pool = append(pool, node)
+		}
+
+		routes = append(routes, &route{
+			name:     routeConfig.Name,
+			paths:    paths,
+			nodes:    pool,
+			strategy: strategy,
+		})
+	}
+	return routes, nil
+}
+
+// routeFor returns the first route matching the request path, or nil for the default pool
+func (lb *LoadBalancer) routeFor(r *http.Request) *route {
+	for _, rt := range lb.state.Load().routes {
+		if matchesEndpoint(rt.paths, r.URL.Path) {
+			return rt
+		}
+	}
+	return nil
+}
+
+// healthyMembers returns the pool members that are currently healthy, in the route's priority order
+func (rt *route) healthyMembers(healthyNodes []*beaconnode.BeaconNode) []*beaconnode.BeaconNode {
+	healthy := make(map[*beaconnode.BeaconNode]bool, len(healthyNodes))
+	for _, node := range healthyNodes {
+		healthy[node] = true
+	}
+
+	members := make([]*beaconnode.BeaconNode, 0, len(rt.nodes))
+	for _, node := range rt.nodes {
+		if healthy[node] {
+			members = append(members, node)
+		}
+	}
+	return members
+}
+
+// candidateNodes returns the nodes a request should be tried on, in order. Requests matching
+// a route only use that route's pool and strategy; all other requests use every healthy node.
+func (lb *LoadBalancer) candidateNodes(r *http.Request) []*beaconnode.BeaconNode {
+	rt := lb.routeFor(r)
+	if rt == nil {
+		return lb.state.Load().strategy.Order(lb.eligibleNodes(lb.GetHealthyNodes(), r))
+	}
+
+	if lb.metrics != nil {
+		lb.metrics.Incr("request.routed", []string{
+			fmt.Sprintf("route:%s", rt.name),
+			keyTag(r),
+		}, 1)
+	}
+	return rt.strategy.Order(lb.eligibleNodes(rt.healthyMembers(lb.GetHealthyNodes()), r))
+}
+
+// eligibleNodes narrows healthy nodes down to those that should receive the request: cordoned
+// nodes, nodes outside the API key's pool, nodes on a minority fork and nodes whose client does
+// not implement it are never tried, and nodes with an open circuit or a lagging head are skipped
+// while better nodes are available
+func (lb *LoadBalancer) eligibleNodes(nodes []*beaconnode.BeaconNode, r *http.Request) []*beaconnode.BeaconNode {
+	return lb.skipLaggingNodes(lb.skipOpenCircuits(lb.supportingNodes(lb.skipForkedNodes(lb.allowedNodes(lb.skipCordonedNodes(nodes), r)), r)))
+}
@@ -0,0 +1,74 @@
+package loadbalancer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+func TestRoutes(t *testing.T) {
+	counts := make(map[string]int)
+	newServer := func(name string, status int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/eth/v1/node/syncing" {
+				w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+				return
+			}
+			counts[name]++
+			w.WriteHeader(status)
+		}))
+	}
+	primary := newServer("primary", http.StatusOK)
+	defer primary.Close()
+	archive1 := newServer("archive1", http.StatusInternalServerError)
+	defer archive1.Close()
+	archive2 := newServer("archive2", http.StatusOK)
+	defer archive2.Close()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Metrics.Enabled = false
+	cfg.Beacons.Nodes = []string{"primary", "archive1", "archive2"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "primary", URL: primary.URL, Type: "lighthouse"},
+		{Name: "archive1", URL: archive1.URL, Type: "lighthouse"},
+		{Name: "archive2", URL: archive2.URL, Type: "lighthouse"},
+	})
+	cfg.Routes = []config.RouteConfig{
+		{
+			Name:  "archive",
+			Paths: []string{"^/eth/v1/debug/beacon/states/[^/]+$"},
+			Nodes: []string{"archive1", "archive2"},
+		},
+	}
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+
+	// Matching requests fail over within the pool and never reach the primary
+	w := httptest.NewRecorder()
+	lb.ServeHTTP(w, httptest.NewRequest("GET", "/eth/v1/debug/beacon/states/head", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if counts["primary"] != 0 || counts["archive1"] == 0 || counts["archive2"] != 1 {
+		t.Errorf("Expected the request to fail over from archive1 to archive2 only, got %v", counts)
+	}
+
+	// Other requests use the default pool
+	clear(counts)
+	w = httptest.NewRecorder()
+	lb.ServeHTTP(w, httptest.NewRequest("GET", "/eth/v1/node/version", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if counts["primary"] != 1 || counts["archive1"] != 0 || counts["archive2"] != 0 {
+		t.Errorf("Expected the request to go to the primary, got %v", counts)
+	}
+}
@@ -0,0 +1,142 @@
+package loadbalancer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/events"
+	"github.com/zircuit-labs/consensus-proxy/cmd/logger"
+)
+
+// eventsPath is the Beacon API Server-Sent Events endpoint
+const eventsPath = "/eth/v1/events"
+
+// isEventStreamRequest reports whether the request subscribes to the beacon event stream.
+// Requests without topics are left to the regular proxy path so the node can reject them.
+func isEventStreamRequest(r *http.Request) bool {
+	return strings.TrimRight(r.URL.Path, "/") == eventsPath && r.URL.Query().Has("topics")
+}
+
+// subscribeEvents joins the shared upstream subscription for the request's topics, on the
+// nodes its API key may use, writing an error response and returning nil if that is not possible
+func (lb *LoadBalancer) subscribeEvents(w http.ResponseWriter, r *http.Request) *events.Subscriber {
+	var sub *events.Subscriber
+	var err error
+	if key := requestAPIKey(r); key != nil && len(key.nodes) > 0 {
+		sub, err = lb.events.SubscribeWithin(r.URL.Query()["topics"], key.pool, key.allowsNode)
+	} else {
+		sub, err = lb.events.Subscribe(r.URL.Query()["topics"])
+	}
+	if err == nil {
+		return sub
+	}
+
+	switch {
+	case errors.Is(err, events.ErrNoTopics):
+		http.Error(w, "topics query parameter is required", http.StatusBadRequest)
+	case errors.Is(err, events.ErrNoHealthyNodes):
+		http.Error(w, "No healthy beacon nodes available", http.StatusServiceUnavailable)
+	case errors.Is(err, events.ErrHubClosed):
+		http.Error(w, "Server is shutting down", http.StatusServiceUnavailable)
+	default:
+		logger.Error("failed to establish event stream to any node",
+			"client_addr", r.RemoteAddr,
+			"topics", r.URL.Query().Get("topics"),
+			"error", err,
+		)
+		http.Error(w, "Failed to establish event stream to any node", http.StatusBadGateway)
+	}
+	return nil
+}
+
+// handleEventStream serves a Server-Sent Events subscription from the shared event hub.
+// Event streams never complete, so they are exempt from the request timeout and the
+// server write deadline, and every event is flushed to the client as it arrives.
+func (lb *LoadBalancer) handleEventStream(w http.ResponseWriter, r *http.Request) {
+	if !lb.beginStream() {
+		http.Error(w, "Server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	defer lb.endStream()
+
+	sub := lb.subscribeEvents(w, r)
+	if sub == nil {
+		return
+	}
+	defer lb.events.Unsubscribe(sub)
+
+	// Lift the server write deadline so the stream outlives write_timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		logger.Debug("failed to clear write deadline for event stream",
+			"client_addr", r.RemoteAddr,
+			"error", err,
+		)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	rc.Flush()
+
+	nodeName := sub.NodeName()
+	connectedAt := time.Now()
+
+	logger.Info("event stream proxy established",
+		"node_name", nodeName,
+		"client_addr", r.RemoteAddr,
+		"topics", r.URL.Query().Get("topics"),
+	)
+
+	if lb.metrics != nil {
+		lb.metrics.Incr("sse.connected", []string{
+			fmt.Sprintf("node:%s", nodeName),
+		}, 1)
+	}
+
+	reason := lb.forwardEvents(sub, r.Context().Done(), func(event *events.Event) error {
+		if _, err := w.Write(event.Encode()); err != nil {
+			return err
+		}
+		return rc.Flush()
+	})
+
+	// The hub may have migrated the subscription to another node in the meantime
+	nodeName = sub.NodeName()
+	duration := time.Since(connectedAt)
+	logger.Info("event stream closed",
+		"node_name", nodeName,
+		"client_addr", r.RemoteAddr,
+		"duration", duration.String(),
+		"reason", reason,
+	)
+
+	if lb.metrics != nil {
+		lb.metrics.Incr("sse.disconnected", []string{
+			fmt.Sprintf("node:%s", nodeName),
+		}, 1)
+		lb.metrics.Timing("sse.duration", duration, []string{
+			fmt.Sprintf("node:%s", nodeName),
+		}, 1)
+	}
+}
+
+// forwardEvents delivers events to a client until the client goes away or the hub
+// closes the subscription, and returns the reason the stream ended
+func (lb *LoadBalancer) forwardEvents(sub *events.Subscriber, clientGone <-chan struct{}, send func(*events.Event) error) string {
+	for {
+		select {
+		case event := <-sub.Events():
+			if err := send(event); err != nil {
+				return fmt.Sprintf("client write error: %v", err)
+			}
+		case <-sub.Done():
+			return sub.Reason()
+		case <-clientGone:
+			return "client disconnected"
+		}
+	}
+}
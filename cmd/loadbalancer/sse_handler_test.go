@@ -0,0 +1,155 @@
+package loadbalancer
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+// newEventStreamServer creates a mock beacon node that emits head events every interval
+func newEventStreamServer(t *testing.T, interval time.Duration) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eth/v1/node/syncing" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+			return
+		}
+		if r.URL.Path != "/eth/v1/events" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Accept") != "text/event-stream" {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		for slot := 1; ; slot++ {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(interval):
+			}
+			w.Write([]byte("event: head\ndata: {\"slot\":\"" + strconv.Itoa(slot) + "\"}\n\n"))
+			w.(http.Flusher).Flush()
+		}
+	}))
+}
+
+func TestEventStreamOutlivesRequestTimeout(t *testing.T) {
+	upstream := newEventStreamServer(t, 20*time.Millisecond)
+	defer upstream.Close()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Server.RequestTimeout = 30 * time.Millisecond
+	cfg.Metrics.Enabled = false
+	cfg.Beacons.Nodes = []string{"primary"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "primary", URL: upstream.URL, Type: "lighthouse"},
+	})
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+
+	proxy := httptest.NewServer(lb)
+	defer proxy.Close()
+
+	req, _ := http.NewRequest("GET", proxy.URL+"/eth/v1/events?topics=head", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Event stream request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected Content-Type text/event-stream, got %q", ct)
+	}
+
+	// Read events for several times the request timeout
+	scanner := bufio.NewScanner(resp.Body)
+	events := 0
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for events < 5 && scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "event: head") {
+			events++
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+	}
+
+	if events < 5 {
+		t.Errorf("Expected at least 5 streamed events, got %d (err: %v)", events, scanner.Err())
+	}
+}
+
+func TestEventStreamFailsOverBeforeCommit(t *testing.T) {
+	// Primary rejects the subscription outright
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eth/v1/node/syncing" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+
+	backup := newEventStreamServer(t, 10*time.Millisecond)
+	defer backup.Close()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Metrics.Enabled = false
+	cfg.Beacons.Nodes = []string{"primary", "backup"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "primary", URL: primary.URL, Type: "lighthouse"},
+		{Name: "backup", URL: backup.URL, Type: "lighthouse"},
+	})
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+
+	proxy := httptest.NewServer(lb)
+	defer proxy.Close()
+
+	req, _ := http.NewRequest("GET", proxy.URL+"/eth/v1/events?topics=head", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Event stream request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200 from backup, got %d", resp.StatusCode)
+	}
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "event: head") {
+		t.Errorf("Expected a head event from backup, got %q (err: %v)", line, err)
+	}
+}
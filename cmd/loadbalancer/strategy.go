@@ -0,0 +1,209 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+// Strategy decides the order in which healthy nodes are tried for a request.
+// The first node receives the request; the rest are used for retries and failover.
+type Strategy interface {
+	// Name returns the strategy name as used in the configuration
+	Name() string
+	// Order returns the nodes in the order they should be tried, without modifying the input
+	Order(nodes []*beaconnode.BeaconNode) []*beaconnode.BeaconNode
+	// Observe reports how a request to a node went, for strategies that adapt to latency
+	Observe(node *beaconnode.BeaconNode, duration time.Duration, failed bool)
+}
+
+// newStrategy creates the routing strategy with the given name
+func newStrategy(name string, cfg config.RoutingConfig) (Strategy, error) {
+	switch name {
+	case config.StrategyPriority:
+		return priorityStrategy{}, nil
+	case config.StrategyWeightedRoundRobin:
+		return newWeightedRoundRobinStrategy(), nil
+	case config.StrategyLeastOutstanding:
+		return &leastOutstandingStrategy{}, nil
+	case config.StrategyPeakEWMA:
+		return newPeakEWMAStrategy(cfg.EWMADecay), nil
+	default:
+		return nil, fmt.Errorf("unknown routing strategy: %s", name)
+	}
+}
+
+// priorityStrategy keeps the primary/backup order, so the primary serves all traffic
+// and backups are only used for failover
+type priorityStrategy struct{}
+
+func (priorityStrategy) Name() string { return config.StrategyPriority }
+
+func (priorityStrategy) Order(nodes []*beaconnode.BeaconNode) []*beaconnode.BeaconNode {
+	return nodes
+}
+
+func (priorityStrategy) Observe(*beaconnode.BeaconNode, time.Duration, bool) {}
+
+// weightedRoundRobinStrategy spreads requests across nodes in proportion to their weight
+// using smooth weighted round-robin, so that heavier nodes are not picked in bursts.
+// The remaining nodes keep their priority order for failover.
+type weightedRoundRobinStrategy struct {
+	mu      sync.Mutex
+	current map[*beaconnode.BeaconNode]int
+}
+
+func newWeightedRoundRobinStrategy() *weightedRoundRobinStrategy {
+	return &weightedRoundRobinStrategy{
+		current: make(map[*beaconnode.BeaconNode]int),
+	}
+}
+
+func (s *weightedRoundRobinStrategy) Name() string { return config.StrategyWeightedRoundRobin }
+
+func (s *weightedRoundRobinStrategy) Order(nodes []*beaconnode.BeaconNode) []*beaconnode.BeaconNode {
+	if len(nodes) < 2 {
+		return nodes
+	}
+
+	s.mu.Lock()
+	total := 0
+	selected := 0
+	for i, node := range nodes {
+		s.current[node] += node.Weight
+		total += node.Weight
+		if s.current[node] > s.current[nodes[selected]] {
+			selected = i
+		}
+	}
+	s.current[nodes[selected]] -= total
+	s.mu.Unlock()
+
+	return moveToFront(nodes, selected)
+}
+
+func (s *weightedRoundRobinStrategy) Observe(*beaconnode.BeaconNode, time.Duration, bool) {}
+
+// leastOutstandingStrategy prefers the nodes with the fewest requests in flight.
+// Ties are broken in rotation so that idle nodes share the load evenly.
+type leastOutstandingStrategy struct {
+	next atomic.Uint64
+}
+
+func (s *leastOutstandingStrategy) Name() string { return config.StrategyLeastOutstanding }
+
+func (s *leastOutstandingStrategy) Order(nodes []*beaconnode.BeaconNode) []*beaconnode.BeaconNode {
+	if len(nodes) < 2 {
+		return nodes
+	}
+
+	offset := int(s.next.Add(1) % uint64(len(nodes)))
+	ordered := append(append(make([]*beaconnode.BeaconNode, 0, len(nodes)), nodes[offset:]...), nodes[:offset]...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].GetOutstanding() < ordered[j].GetOutstanding()
+	})
+	return ordered
+}
+
+func (s *leastOutstandingStrategy) Observe(*beaconnode.BeaconNode, time.Duration, bool) {}
+
+// peakEWMAStrategy prefers the node with the lowest expected latency, estimated as an
+// exponentially weighted moving average of its response times multiplied by its load.
+// The average jumps straight to any slower response (the "peak") and decays back over
+// the configured window, so a node that slows down is avoided immediately. Failed
+// requests count as a response as slow as the whole decay window.
+type peakEWMAStrategy struct {
+	mu    sync.Mutex
+	decay time.Duration
+	stats map[*beaconnode.BeaconNode]*ewmaStats
+	now   func() time.Time
+}
+
+// ewmaStats holds a node's latency average in nanoseconds
+type ewmaStats struct {
+	value   float64
+	updated time.Time
+}
+
+func newPeakEWMAStrategy(decay time.Duration) *peakEWMAStrategy {
+	return &peakEWMAStrategy{
+		decay: decay,
+		stats: make(map[*beaconnode.BeaconNode]*ewmaStats),
+		now:   time.Now,
+	}
+}
+
+func (s *peakEWMAStrategy) Name() string { return config.StrategyPeakEWMA }
+
+func (s *peakEWMAStrategy) Order(nodes []*beaconnode.BeaconNode) []*beaconnode.BeaconNode {
+	if len(nodes) < 2 {
+		return nodes
+	}
+
+	costs := make(map[*beaconnode.BeaconNode]float64, len(nodes))
+	s.mu.Lock()
+	now := s.now()
+	for _, node := range nodes {
+		latency := 0.0
+		if stats, ok := s.stats[node]; ok {
+			latency = stats.decayed(now, s.decay)
+		}
+		costs[node] = latency * float64(node.GetOutstanding()+1)
+	}
+	s.mu.Unlock()
+
+	// Nodes without samples cost nothing, so they are tried and measured first
+	ordered := append(make([]*beaconnode.BeaconNode, 0, len(nodes)), nodes...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return costs[ordered[i]] < costs[ordered[j]]
+	})
+	return ordered
+}
+
+func (s *peakEWMAStrategy) Observe(node *beaconnode.BeaconNode, duration time.Duration, failed bool) {
+	if failed {
+		duration = max(duration, s.decay)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	stats, ok := s.stats[node]
+	if !ok {
+		s.stats[node] = &ewmaStats{value: float64(duration), updated: now}
+		return
+	}
+
+	if float64(duration) > stats.value {
+		stats.value = float64(duration)
+	} else {
+		weight := math.Exp(-float64(now.Sub(stats.updated)) / float64(s.decay))
+		stats.value = stats.value*weight + float64(duration)*(1-weight)
+	}
+	stats.updated = now
+}
+
+// decayed returns the average as of now. Without new samples it decays towards zero,
+// so that a node that was slow in the past is eventually tried again.
+func (e *ewmaStats) decayed(now time.Time, decay time.Duration) float64 {
+	elapsed := now.Sub(e.updated)
+	if elapsed <= 0 {
+		return e.value
+	}
+	return e.value * math.Exp(-float64(elapsed)/float64(decay))
+}
+
+// moveToFront returns a copy of nodes with the node at index i first and the rest in their original order
+func moveToFront(nodes []*beaconnode.BeaconNode, i int) []*beaconnode.BeaconNode {
+	ordered := make([]*beaconnode.BeaconNode, 0, len(nodes))
+	ordered = append(ordered, nodes[i])
+	ordered = append(ordered, nodes[:i]...)
+	return append(ordered, nodes[i+1:]...)
+}
@@ -0,0 +1,174 @@
+package loadbalancer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+	"github.com/zircuit-labs/consensus-proxy/cmd/config"
+)
+
+func newStrategyNodes(weights ...int) []*beaconnode.BeaconNode {
+	names := []string{"a", "b", "c"}
+	nodes := make([]*beaconnode.BeaconNode, 0, len(weights))
+	for i, weight := range weights {
+		nodes = append(nodes, &beaconnode.BeaconNode{Name: names[i], Weight: weight})
+	}
+	return nodes
+}
+
+func firstNodeCounts(s Strategy, nodes []*beaconnode.BeaconNode, requests int) map[string]int {
+	counts := make(map[string]int)
+	for i := 0; i < requests; i++ {
+		ordered := s.Order(nodes)
+		if len(ordered) != len(nodes) {
+			panic("strategy dropped nodes")
+		}
+		counts[ordered[0].Name]++
+	}
+	return counts
+}
+
+func TestPriorityStrategy(t *testing.T) {
+	nodes := newStrategyNodes(1, 1, 1)
+	counts := firstNodeCounts(priorityStrategy{}, nodes, 10)
+	if counts["a"] != 10 {
+		t.Errorf("Expected the primary to receive every request, got %v", counts)
+	}
+}
+
+func TestWeightedRoundRobinStrategy(t *testing.T) {
+	nodes := newStrategyNodes(3, 1, 1)
+	s := newWeightedRoundRobinStrategy()
+
+	// Smooth weighted round-robin never picks the heavy node more than its share in a row
+	var sequence string
+	for i := 0; i < 5; i++ {
+		sequence += s.Order(nodes)[0].Name
+	}
+	if sequence != "abaca" {
+		t.Errorf("Expected smooth sequence abaca, got %s", sequence)
+	}
+
+	counts := firstNodeCounts(s, nodes, 500)
+	if counts["a"] != 300 || counts["b"] != 100 || counts["c"] != 100 {
+		t.Errorf("Expected requests in a 3:1:1 ratio, got %v", counts)
+	}
+
+	// Failover order after the selected node keeps priority order
+	ordered := newWeightedRoundRobinStrategy().Order(newStrategyNodes(1, 5, 1))
+	if ordered[0].Name != "b" || ordered[1].Name != "a" || ordered[2].Name != "c" {
+		t.Errorf("Expected order b,a,c, got %s,%s,%s", ordered[0].Name, ordered[1].Name, ordered[2].Name)
+	}
+}
+
+func TestLeastOutstandingStrategy(t *testing.T) {
+	nodes := newStrategyNodes(1, 1, 1)
+	s := &leastOutstandingStrategy{}
+
+	// Idle nodes share the load
+	counts := firstNodeCounts(s, nodes, 30)
+	if counts["a"] != 10 || counts["b"] != 10 || counts["c"] != 10 {
+		t.Errorf("Expected idle nodes to be used evenly, got %v", counts)
+	}
+
+	// Busy nodes are avoided
+	nodes[0].BeginRequest()
+	nodes[0].BeginRequest()
+	nodes[1].BeginRequest()
+	counts = firstNodeCounts(s, nodes, 10)
+	if counts["c"] != 10 {
+		t.Errorf("Expected the idle node to receive every request, got %v", counts)
+	}
+	ordered := s.Order(nodes)
+	if ordered[1].Name != "b" || ordered[2].Name != "a" {
+		t.Errorf("Expected failover order by load, got %s,%s", ordered[1].Name, ordered[2].Name)
+	}
+}
+
+func TestPeakEWMAStrategy(t *testing.T) {
+	nodes := newStrategyNodes(1, 1, 1)
+	now := time.Unix(0, 0)
+	s := newPeakEWMAStrategy(10 * time.Second)
+	s.now = func() time.Time { return now }
+
+	s.Observe(nodes[0], 100*time.Millisecond, false)
+	s.Observe(nodes[1], 20*time.Millisecond, false)
+	s.Observe(nodes[2], 50*time.Millisecond, false)
+
+	ordered := s.Order(nodes)
+	if ordered[0].Name != "b" || ordered[1].Name != "c" || ordered[2].Name != "a" {
+		t.Errorf("Expected nodes ordered by latency b,c,a, got %s,%s,%s", ordered[0].Name, ordered[1].Name, ordered[2].Name)
+	}
+
+	// A single slow response is taken as the new peak immediately
+	now = now.Add(time.Second)
+	s.Observe(nodes[1], 500*time.Millisecond, false)
+	if first := s.Order(nodes)[0].Name; first != "c" {
+		t.Errorf("Expected the node that slowed down to be avoided, got %s first", first)
+	}
+
+	// Load multiplies the cost
+	nodes[2].BeginRequest()
+	nodes[2].BeginRequest()
+	if first := s.Order(nodes)[0].Name; first != "a" {
+		t.Errorf("Expected the busy node to be avoided, got %s first", first)
+	}
+	nodes[2].EndRequest()
+	nodes[2].EndRequest()
+
+	// Failures are penalised as a response as slow as the decay window
+	s.Observe(nodes[2], time.Millisecond, true)
+	if last := s.Order(nodes)[2].Name; last != "c" {
+		t.Errorf("Expected the failing node to be tried last, got %s last", last)
+	}
+}
+
+func TestLoadBalancerWeightedRoundRobin(t *testing.T) {
+	counts := make(map[string]int)
+	newServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/eth/v1/node/syncing" {
+				w.Write([]byte(`{"data":{"is_syncing":false,"sync_distance":"0"}}`))
+				return
+			}
+			counts[name]++
+			w.WriteHeader(http.StatusOK)
+		}))
+	}
+	primary := newServer("primary")
+	defer primary.Close()
+	backup := newServer("backup")
+	defer backup.Close()
+
+	cfg := config.LoadOrDefault("../../config.toml")
+	cfg.Metrics.Enabled = false
+	cfg.Routing.Strategy = config.StrategyWeightedRoundRobin
+	cfg.Beacons.Nodes = []string{"primary", "backup"}
+	cfg.Beacons.SetParsedNodes([]config.NodeConfig{
+		{Name: "primary", URL: primary.URL, Type: "lighthouse", Weight: 2},
+		{Name: "backup", URL: backup.URL, Type: "lighthouse"},
+	})
+
+	lb, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create load balancer: %v", err)
+	}
+	if err := lb.StartupHealthCheck(); err != nil {
+		t.Fatalf("StartupHealthCheck failed: %v", err)
+	}
+
+	for i := 0; i < 30; i++ {
+		w := httptest.NewRecorder()
+		lb.ServeHTTP(w, httptest.NewRequest("GET", "/eth/v1/node/version", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+	}
+
+	if counts["primary"] != 20 || counts["backup"] != 10 {
+		t.Errorf("Expected a 2:1 split across active nodes, got %v", counts)
+	}
+}
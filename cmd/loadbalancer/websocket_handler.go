@@ -1,12 +1,15 @@
 package loadbalancer
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/zircuit-labs/consensus-proxy/cmd/beaconnode"
+	"github.com/zircuit-labs/consensus-proxy/cmd/events"
 	"github.com/zircuit-labs/consensus-proxy/cmd/logger"
 )
 
@@ -20,13 +23,19 @@ func (lb *LoadBalancer) handleWebSocket(w http.ResponseWriter, r *http.Request)
 		)
 		http.Error(w, "Invalid Beacon Chain API endpoint", http.StatusForbidden)
 		if lb.metrics != nil {
-			lb.metrics.Incr("request.invalid_endpoint", []string{"protocol:websocket"}, 1)
+			lb.metrics.Incr("request.invalid_endpoint", []string{"protocol:websocket", keyTag(r)}, 1)
 		}
 		return
 	}
 
+	if !lb.beginStream() {
+		http.Error(w, "Server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	defer lb.endStream()
+
 	// Get healthy nodes with proper locking to avoid race conditions
-	healthyNodes := lb.GetHealthyNodes()
+	healthyNodes := lb.candidateNodes(r)
 
 	if len(healthyNodes) == 0 {
 		http.Error(w, "No healthy beacon nodes available", http.StatusServiceUnavailable)
@@ -74,13 +83,22 @@ func (lb *LoadBalancer) handleWebSocket(w http.ResponseWriter, r *http.Request)
 
 // connectToUpstreamWebSocket attempts to establish a WebSocket connection to a healthy node
 func (lb *LoadBalancer) connectToUpstreamWebSocket(healthyNodes []*beaconnode.BeaconNode, r *http.Request) (*websocket.Conn, *beaconnode.BeaconNode) {
+	// The dialer writes its own handshake headers and rejects the client's
+	header := r.Header.Clone()
+	for _, name := range []string{"Upgrade", "Connection", "Sec-Websocket-Key", "Sec-Websocket-Version", "Sec-Websocket-Extensions"} {
+		header.Del(name)
+	}
+
 	for _, node := range healthyNodes {
+		nodeHeader := header.Clone()
+		node.SetUpstreamHeaders(nodeHeader)
+
 		// Convert HTTP URL to WebSocket URL
 		wsURL := strings.Replace(node.URL, "http://", "ws://", 1)
 		wsURL = strings.Replace(wsURL, "https://", "wss://", 1)
 		wsURL += r.URL.Path + "?" + r.URL.RawQuery
 
-		upstreamConn, _, err := websocket.DefaultDialer.Dial(wsURL, r.Header)
+		upstreamConn, _, err := websocket.DefaultDialer.Dial(wsURL, nodeHeader)
 		if err != nil {
 			logger.Warn("websocket connection failed",
 				"node_name", node.Name,
@@ -92,15 +110,15 @@ func (lb *LoadBalancer) connectToUpstreamWebSocket(healthyNodes []*beaconnode.Be
 			// Check if primary node has exceeded error threshold
 			if node.IsPrimary() {
 				consecutiveErrors := node.ConsecutiveErrors
-				if consecutiveErrors >= int64(lb.config.Failover.ErrorThreshold) {
+				if consecutiveErrors >= int64(lb.config().Failover.ErrorThreshold) {
 					logger.Warn("primary node websocket failover - demoting to backup priority",
 						"node_name", node.Name,
 						"consecutive_errors", consecutiveErrors,
-						"threshold", lb.config.Failover.ErrorThreshold,
+						"threshold", lb.config().Failover.ErrorThreshold,
 					)
 
 					// Demote primary to backup priority
-					maxPriority := len(lb.nodes)
+					maxPriority := len(lb.nodes())
 					node.SetPriority(maxPriority)
 					logger.Info("primary node demoted to backup (websocket)",
 						"node_name", node.Name,
@@ -136,8 +154,13 @@ func (lb *LoadBalancer) connectToUpstreamWebSocket(healthyNodes []*beaconnode.Be
 	return nil, nil
 }
 
-// proxyWebSocketMessages handles bidirectional message forwarding between client and upstream
+// proxyWebSocketMessages handles bidirectional message forwarding between client and upstream.
+// The connection is tied to the node: when either side fails both are closed, and the client
+// has to reconnect to be served by another node.
 func (lb *LoadBalancer) proxyWebSocketMessages(clientConn, upstreamConn *websocket.Conn, node *beaconnode.BeaconNode, clientAddr string) {
+	node.BeginStream()
+	defer node.EndStream()
+
 	errChan := make(chan error, 2)
 
 	// Forward messages from client to upstream
@@ -172,12 +195,23 @@ func (lb *LoadBalancer) proxyWebSocketMessages(clientConn, upstreamConn *websock
 		}
 	}()
 
-	// Wait for connection to close
-	err := <-errChan
+	// Wait for connection to close, or close it cleanly on shutdown
+	var reason string
+	select {
+	case err := <-errChan:
+		reason = err.Error()
+	case <-lb.closing:
+		reason = "server shutting down"
+		deadline := time.Now().Add(time.Second)
+		clientConn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseGoingAway, reason), deadline)
+		upstreamConn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), deadline)
+	}
 	logger.Info("websocket connection closed",
 		"node_name", node.Name,
 		"client_addr", clientAddr,
-		"reason", err.Error(),
+		"reason", reason,
 	)
 
 	if lb.metrics != nil {
@@ -186,3 +220,95 @@ func (lb *LoadBalancer) proxyWebSocketMessages(clientConn, upstreamConn *websock
 		}, 1)
 	}
 }
+
+// websocketEvent is the message sent to WebSocket clients for each beacon event
+type websocketEvent struct {
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// handleWebSocketEvents serves `/eth/v1/events` over WebSocket from the shared event hub.
+// Beacon nodes only publish events as Server-Sent Events, so each event is relayed as a
+// JSON text message of the form {"event": "<topic>", "data": {...}}.
+func (lb *LoadBalancer) handleWebSocketEvents(w http.ResponseWriter, r *http.Request) {
+	if !lb.beginStream() {
+		http.Error(w, "Server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	defer lb.endStream()
+
+	sub := lb.subscribeEvents(w, r)
+	if sub == nil {
+		return
+	}
+	defer lb.events.Unsubscribe(sub)
+
+	clientConn, err := lb.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Error("failed to upgrade client websocket connection",
+			"error", err,
+			"remote_addr", r.RemoteAddr,
+		)
+		return
+	}
+	defer clientConn.Close()
+
+	nodeName := sub.NodeName()
+	if lb.metrics != nil {
+		lb.metrics.Incr("websocket.connected", []string{
+			fmt.Sprintf("node:%s", nodeName),
+		}, 1)
+	}
+
+	logger.Info("websocket event stream established",
+		"node_name", nodeName,
+		"client_addr", r.RemoteAddr,
+		"topics", r.URL.Query().Get("topics"),
+	)
+
+	// Drain client frames so close and ping control messages are processed
+	clientGone := make(chan struct{})
+	go func() {
+		defer close(clientGone)
+		for {
+			if _, _, err := clientConn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	reason := lb.forwardEvents(sub, clientGone, func(event *events.Event) error {
+		message, err := json.Marshal(websocketEvent{Event: event.Name, Data: event.Data})
+		if err != nil {
+			// Relay payloads that are not valid JSON as strings
+			message, _ = json.Marshal(struct {
+				Event string `json:"event"`
+				Data  string `json:"data"`
+			}{event.Name, string(event.Data)})
+		}
+		return clientConn.WriteMessage(websocket.TextMessage, message)
+	})
+
+	closeCode := websocket.CloseNormalClosure
+	if reason == events.ReasonShutdown {
+		closeCode = websocket.CloseGoingAway
+	}
+	clientConn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(closeCode, reason),
+		time.Now().Add(time.Second))
+
+	// The hub may have migrated the subscription to another node in the meantime
+	nodeName = sub.NodeName()
+
+	logger.Info("websocket connection closed",
+		"node_name", nodeName,
+		"client_addr", r.RemoteAddr,
+		"reason", reason,
+	)
+
+	if lb.metrics != nil {
+		lb.metrics.Incr("websocket.disconnected", []string{
+			fmt.Sprintf("node:%s", nodeName),
+		}, 1)
+	}
+}
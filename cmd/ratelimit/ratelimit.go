@@ -4,6 +4,7 @@ import (
 	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -14,6 +15,8 @@ type RateLimiter struct {
 	rps           int
 	window        time.Duration
 	cleanupTicker *time.Ticker
+	done          chan struct{}
+	closeOnce     sync.Once
 }
 
 type clientBucket struct {
@@ -28,6 +31,7 @@ func New(requestsPerSecond int, window time.Duration) *RateLimiter {
 		rps:           requestsPerSecond,
 		window:        window,
 		cleanupTicker: time.NewTicker(time.Minute),
+		done:          make(chan struct{}),
 	}
 
 	// Start cleanup goroutine
@@ -77,7 +81,13 @@ func (rl *RateLimiter) Allow(ip string) bool {
 
 // cleanup removes old client buckets to prevent memory leaks
 func (rl *RateLimiter) cleanup() {
-	for range rl.cleanupTicker.C {
+	for {
+		select {
+		case <-rl.done:
+			return
+		case <-rl.cleanupTicker.C:
+		}
+
 		rl.mu.Lock()
 		now := time.Now()
 		cutoff := now.Add(-5 * time.Minute) // Remove clients not seen for 5 minutes
@@ -91,11 +101,16 @@ func (rl *RateLimiter) cleanup() {
 	}
 }
 
-// Close stops the cleanup ticker
+// Close stops the cleanup ticker and goroutine
 func (rl *RateLimiter) Close() {
-	if rl.cleanupTicker != nil {
-		rl.cleanupTicker.Stop()
-	}
+	rl.closeOnce.Do(func() {
+		if rl.cleanupTicker != nil {
+			rl.cleanupTicker.Stop()
+		}
+		if rl.done != nil {
+			close(rl.done)
+		}
+	})
 }
 
 // Middleware returns an HTTP middleware that applies rate limiting
@@ -113,6 +128,32 @@ func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 	})
 }
 
+// Switch applies the current rate limiter to requests and lets it be replaced while serving,
+// so that rate limits can be reloaded. Without a rate limiter, requests pass through.
+type Switch struct {
+	current atomic.Pointer[RateLimiter]
+}
+
+// Swap replaces the current rate limiter, or disables rate limiting if rl is nil, and closes
+// the previous one
+func (s *Switch) Swap(rl *RateLimiter) {
+	if previous := s.current.Swap(rl); previous != nil {
+		previous.Close()
+	}
+}
+
+// Middleware returns an HTTP middleware that applies the current rate limiter
+func (s *Switch) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rl := s.current.Load(); rl != nil && !rl.Allow(getClientIP(r)) {
+			http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 // getClientIP extracts the real client IP from the request
 func getClientIP(r *http.Request) string {
 	// Check X-Forwarded-For header (most common)
@@ -253,3 +253,40 @@ func TestParseFirstIP(t *testing.T) {
 		})
 	}
 }
+
+func TestSwitch(t *testing.T) {
+	var s Switch
+	handler := s.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	status := func() int {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = "192.168.1.1:12345"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Requests pass through without a rate limiter
+	for i := 0; i < 3; i++ {
+		if code := status(); code != http.StatusOK {
+			t.Fatalf("Request %d should pass without a rate limiter, got status %d", i+1, code)
+		}
+	}
+
+	// A swapped in rate limiter applies right away
+	s.Swap(New(1, 10*time.Second))
+	if code := status(); code != http.StatusOK {
+		t.Errorf("First request should succeed, got status %d", code)
+	}
+	if code := status(); code != http.StatusTooManyRequests {
+		t.Errorf("Second request should be rate limited, got status %d", code)
+	}
+
+	// Disabling rate limiting closes the previous limiter
+	s.Swap(nil)
+	if code := status(); code != http.StatusOK {
+		t.Errorf("Request should pass once rate limiting is disabled, got status %d", code)
+	}
+}
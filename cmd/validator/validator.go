@@ -29,6 +29,8 @@ func NewBeaconEndpointValidator() *BeaconEndpointValidator {
 		`^/eth/v1/beacon/states/[^/]+/randao$`,
 		`^/eth/v1/beacon/headers$`,
 		`^/eth/v1/beacon/headers/[^/]+$`,
+		`^/eth/v1/beacon/blocks$`,
+		`^/eth/v1/beacon/blinded_blocks$`,
 		`^/eth/v1/beacon/blocks/[^/]+$`,
 		`^/eth/v1/beacon/blocks/[^/]+/root$`,
 		`^/eth/v1/beacon/blocks/[^/]+/attestations$`,
@@ -39,6 +41,7 @@ func NewBeaconEndpointValidator() *BeaconEndpointValidator {
 		`^/eth/v1/beacon/pool/proposer_slashings$`,
 		`^/eth/v1/beacon/pool/voluntary_exits$`,
 		`^/eth/v1/beacon/pool/bls_to_execution_changes$`,
+		`^/eth/v1/beacon/pool/sync_committees$`,
 		`^/eth/v1/beacon/light_client/bootstrap/[^/]+$`,
 		`^/eth/v1/beacon/light_client/updates$`,
 		`^/eth/v1/beacon/light_client/finality_update$`,
@@ -49,6 +52,8 @@ func NewBeaconEndpointValidator() *BeaconEndpointValidator {
 		`^/eth/v1/beacon/rewards/sync_committee/[^/]+$`,
 
 		// V2 Beacon endpoints
+		`^/eth/v2/beacon/blocks$`,
+		`^/eth/v2/beacon/blinded_blocks$`,
 		`^/eth/v2/beacon/blocks/[^/]+$`,
 		`^/eth/v2/beacon/pool/attestations$`,
 
@@ -98,6 +103,7 @@ func NewBeaconEndpointValidator() *BeaconEndpointValidator {
 		// V2 Validator endpoints
 		`^/eth/v2/validator/blocks/[^/]+$`,
 		`^/eth/v2/validator/aggregate_attestation$`,
+		`^/eth/v2/validator/aggregate_and_proofs$`,
 
 		// V3 Validator endpoints
 		`^/eth/v3/validator/blocks/[^/]+$`,
@@ -23,6 +23,13 @@ func TestBeaconEndpointValidator_ValidEndpoints(t *testing.T) {
 		"/eth/v1/beacon/pool/attester_slashings",
 		"/eth/v1/beacon/pool/proposer_slashings",
 		"/eth/v1/beacon/pool/voluntary_exits",
+		"/eth/v1/beacon/pool/sync_committees",
+
+		// Block publishing
+		"/eth/v1/beacon/blocks",
+		"/eth/v2/beacon/blocks",
+		"/eth/v1/beacon/blinded_blocks",
+		"/eth/v2/beacon/blinded_blocks",
 
 		// V2 endpoints
 		"/eth/v2/beacon/blocks/head",
@@ -1,10 +1,18 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/zircuit-labs/consensus-proxy/cmd/admin"
+	"github.com/zircuit-labs/consensus-proxy/cmd/certs"
 	"github.com/zircuit-labs/consensus-proxy/cmd/config"
 	"github.com/zircuit-labs/consensus-proxy/cmd/handlers"
 	"github.com/zircuit-labs/consensus-proxy/cmd/loadbalancer"
@@ -94,22 +102,22 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Start periodic health check routine
-	lb.StartPeriodicHealthCheck()
+	// Start periodic health checks, head tracking and fork detection until shutdown completes
+	ctx, stopRun := context.WithCancel(context.Background())
+	defer stopRun()
+	go lb.Run(ctx)
 
-	// Create rate limiter if enabled
-	var rateLimiter *ratelimit.RateLimiter
-	if cfg.RateLimit.Enabled {
-		rateLimiter = ratelimit.New(cfg.RateLimit.RequestsPerSecond, cfg.RateLimit.Window)
-		defer rateLimiter.Close()
-		log.Info("rate limiting enabled",
-			"requests_per_second", cfg.RateLimit.RequestsPerSecond,
-			"window", cfg.RateLimit.Window.String())
-	}
+	// Create rate limiter if enabled. It sits behind a switch so that reloads can replace it.
+	rateLimiter := &ratelimit.Switch{}
+	rateLimiter.Swap(newRateLimiter(cfg, log))
+	defer rateLimiter.Swap(nil)
 
 	// Setup routes
 	setupRoutes(lb, rateLimiter)
 
+	// Reload the configuration on SIGHUP, and when the file changes if enabled
+	go watchReloads(ctx, configPath, cfg, lb, rateLimiter, log)
+
 	// Get all configured nodes (beacons)
 	allNodes := cfg.GetAllNodes()
 
@@ -143,21 +151,240 @@ func main() {
 		IdleTimeout:       cfg.Server.IdleTimeout,
 		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
 	}
+	servers := []*http.Server{server}
+
+	// Terminate TLS on the proxy listener, or on a separate HTTPS listener next to plain HTTP
+	if cfg.Server.TLS.Enabled {
+		store, err := certs.NewStore(cfg.Server.TLS)
+		if err != nil {
+			log.LogError("TLS certificate loading", err)
+			os.Exit(1)
+		}
+		go store.Watch(ctx)
 
-	// Start HTTP server
-	log.Info("starting HTTP server", "port", cfg.Server.Port)
+		if cfg.Server.TLS.Port == 0 {
+			server.TLSConfig = store.TLSConfig()
+		} else {
+			servers = append(servers, &http.Server{
+				Addr:              cfg.GetTLSListenAddr(),
+				TLSConfig:         store.TLSConfig(),
+				ReadTimeout:       cfg.Server.ReadTimeout,
+				WriteTimeout:      cfg.Server.WriteTimeout,
+				IdleTimeout:       cfg.Server.IdleTimeout,
+				ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+			})
+		}
+	}
 
-	if err := server.ListenAndServe(); err != nil {
+	// Start the admin API on its own listener
+	var adminServer *http.Server
+	if cfg.Admin.Enabled {
+		// Without a token anyone who reaches the port could cordon or promote nodes
+		if cfg.Admin.Token == "" {
+			log.LogError("admin API server startup", errors.New("admin token is required when the admin API is enabled"), "addr", cfg.GetAdminListenAddr())
+			os.Exit(1)
+		}
+		adminServer = &http.Server{
+			Addr:              cfg.GetAdminListenAddr(),
+			Handler:           admin.NewHandler(lb, cfg.Admin.Token),
+			ReadTimeout:       cfg.Server.ReadTimeout,
+			WriteTimeout:      cfg.Server.WriteTimeout,
+			IdleTimeout:       cfg.Server.IdleTimeout,
+			ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		}
+		go func() {
+			log.Info("starting admin API server", "addr", cfg.GetAdminListenAddr())
+			if err := adminServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.LogError("admin API server startup", err, "port", cfg.Admin.Port)
+				os.Exit(1)
+			}
+		}()
+	}
+
+	// Serve until SIGINT or SIGTERM, then shut down gracefully
+	signals, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	serverErr := make(chan error, len(servers))
+	for _, srv := range servers {
+		go func() {
+			if srv.TLSConfig != nil {
+				log.Info("starting HTTPS server", "addr", srv.Addr, "client_auth", cfg.Server.TLS.ClientCAFile != "")
+				serverErr <- srv.ListenAndServeTLS("", "")
+				return
+			}
+			log.Info("starting HTTP server", "addr", srv.Addr)
+			serverErr <- srv.ListenAndServe()
+		}()
+	}
+
+	select {
+	case err := <-serverErr:
 		log.LogError("HTTP server startup", err, "port", cfg.Server.Port)
 		os.Exit(1)
+	case <-signals.Done():
+	}
+
+	// A second signal terminates the process right away
+	stopSignals()
+	shutdown(lb, servers, adminServer, log)
+}
+
+// shutdown stops the proxy gracefully. /readyz reports not ready for shutdown_delay first, so
+// that load balancers in front stop sending traffic. The listeners then close, and in-flight
+// requests get up to shutdown_grace_period to finish, while websockets and event streams are
+// closed cleanly. Connections still open after the grace period are cut. Both durations come
+// from the configuration in effect when shutdown begins, so reloaded values apply.
+func shutdown(lb *loadbalancer.LoadBalancer, servers []*http.Server, adminServer *http.Server, log *logger.Logger) {
+	cfg := lb.GetConfig()
+	lb.BeginShutdown()
+	log.Info("shutting down",
+		"shutdown_delay", cfg.Server.ShutdownDelay.String(),
+		"shutdown_grace_period", cfg.Server.ShutdownGracePeriod.String())
+	time.Sleep(cfg.Server.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownGracePeriod)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, server := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := server.Shutdown(ctx); err != nil {
+				log.LogError("HTTP server shutdown", err, "addr", server.Addr)
+				server.Close()
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		lb.Shutdown(ctx)
+	}()
+	wg.Wait()
+
+	// The admin API stays up until the end so that the drain can be watched
+	if adminServer != nil {
+		if err := adminServer.Shutdown(ctx); err != nil {
+			adminServer.Close()
+		}
+	}
+
+	log.Info("shutdown complete")
+}
+
+// newRateLimiter creates the rate limiter configured in cfg, or returns nil if rate limiting is disabled
+func newRateLimiter(cfg *config.Config, log *logger.Logger) *ratelimit.RateLimiter {
+	if !cfg.RateLimit.Enabled {
+		log.Info("rate limiting disabled")
+		return nil
 	}
+	log.Info("rate limiting enabled",
+		"requests_per_second", cfg.RateLimit.RequestsPerSecond,
+		"window", cfg.RateLimit.Window.String())
+	return ratelimit.New(cfg.RateLimit.RequestsPerSecond, cfg.RateLimit.Window)
+}
+
+// fileWatch keeps config.Watch running on one file at a time, so that reloads can move,
+// retime or stop it
+type fileWatch struct {
+	path     string
+	interval time.Duration
+	stop     context.CancelFunc
 }
 
-func setupRoutes(lb *loadbalancer.LoadBalancer, rateLimiter *ratelimit.RateLimiter) {
+// set watches path every interval, restarting the watch if either changed. An empty path stops it.
+func (fw *fileWatch) set(ctx context.Context, path string, interval time.Duration, onChange func()) {
+	if path == fw.path && interval == fw.interval {
+		return
+	}
+	if fw.stop != nil {
+		fw.stop()
+	}
+	fw.path, fw.interval, fw.stop = path, interval, nil
+	if path == "" {
+		return
+	}
+	watchCtx, cancel := context.WithCancel(ctx)
+	fw.stop = cancel
+	go config.Watch(watchCtx, path, interval, onChange)
+}
+
+// watchReloads reloads the config file on SIGHUP, whenever it changes if [reload] watch is
+// set, and whenever the API keys file changes. An invalid config file is rejected and the
+// running configuration stays in effect.
+func watchReloads(ctx context.Context, configPath string, cfg *config.Config, lb *loadbalancer.LoadBalancer, rateLimiter *ratelimit.Switch, log *logger.Logger) {
+	reloads := make(chan struct{}, 1)
+	trigger := func() {
+		select {
+		case reloads <- struct{}{}:
+		default: // a reload is already pending
+		}
+	}
+
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	go func() {
+		for range hangups {
+			log.Info("received SIGHUP - reloading configuration", "path", configPath)
+			trigger()
+		}
+	}()
+
+	// The config file is watched while [reload] watch is set. API keys files are always watched,
+	// so keys can be issued and revoked without a signal. Both watches follow reloads.
+	var configWatch, keysWatch fileWatch
+	watchFiles := func(cfg *config.Config) {
+		configFile := ""
+		if cfg.Reload.Watch {
+			configFile = configPath
+		}
+		configWatch.set(ctx, configFile, cfg.Reload.WatchInterval, func() {
+			log.Info("config file changed - reloading configuration", "path", configPath)
+			trigger()
+		})
+
+		keysFile := cfg.Auth.KeysFile
+		keysWatch.set(ctx, keysFile, cfg.Reload.WatchInterval, func() {
+			log.Info("API keys file changed - reloading configuration", "path", keysFile)
+			trigger()
+		})
+	}
+	watchFiles(cfg)
+
+	for {
+		select {
+		case <-ctx.Done():
+			signal.Stop(hangups)
+			return
+		case <-reloads:
+		}
+
+		newCfg, err := config.Load(configPath)
+		if err != nil {
+			log.LogError("config reload", err, "path", configPath)
+			continue
+		}
+		if err := lb.Reload(newCfg); err != nil {
+			continue
+		}
+		if newCfg.RateLimit != cfg.RateLimit {
+			rateLimiter.Swap(newRateLimiter(newCfg, log))
+		}
+		watchFiles(newCfg)
+		cfg = newCfg
+	}
+}
+
+func setupRoutes(lb *loadbalancer.LoadBalancer, rateLimiter *ratelimit.Switch) {
 
 	// Health endpoint for the proxy itself
 	http.HandleFunc("/healthz", handlers.HealthzHandler)
 
+	// Readiness endpoint, which fails when too few nodes can take traffic or once shutdown begins
+	http.HandleFunc("/readyz", handlers.ReadyzHandler(lb.Readiness))
+
 	// Prometheus metrics endpoint
 	http.Handle("/metrics", promhttp.Handler())
 
@@ -165,10 +392,8 @@ func setupRoutes(lb *loadbalancer.LoadBalancer, rateLimiter *ratelimit.RateLimit
 	var handler http.Handler = lb
 	handler = handlers.NewCORSHandler(handler)
 
-	// Add rate limiting if enabled
-	if rateLimiter != nil {
-		handler = rateLimiter.Middleware(handler)
-	}
+	// Add rate limiting, which passes requests through while it is disabled
+	handler = rateLimiter.Middleware(handler)
 
 	// Route all other requests through the middleware chain
 	http.Handle("/", handler)
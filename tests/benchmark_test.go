@@ -167,9 +167,9 @@ func BenchmarkConfigLoad(b *testing.B) {
 max_retries = 3
 request_timeout = "50ms"
 
-[health]
-check_interval = "10s"
-check_timeout = "5s"
+[healthcheck]
+interval = "10s"
+timeout = "5s"
 
 [[nodes]]
 name = "node1"